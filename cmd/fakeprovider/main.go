@@ -0,0 +1,57 @@
+// Command fakeprovider serves fake TMDB and OMDB search APIs from a catalogue, for end-to-end tests
+// and for clients written in other languages.
+//
+// Usage:
+//
+//	fakeprovider [-addr :8080] [-catalogue titles.json] [-tmdb-key key] [-omdb-key key]
+//
+// The catalogue is a JSON array of search results, e.g. [{"Title": "The Matrix", "Year": "1999",
+// "ImdbID": "tt0133093", "TmdbID": "603", "Type": "movie"}]; a built-in catalogue is served by default.
+// Point TMDB clients at http://<addr>/3/search/multi (or /3/search/movie and /3/search/tv) and OMDB clients at http://<addr>/?s=.
+// Failures can be injected with, e.g., curl -X POST "http://<addr>/_fakeprovider/inject?status=429&count=3".
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/jdahan/gogettitles/search"
+	"github.com/jdahan/gogettitles/search/searchtest"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "the address to listen on")
+	cataloguePath := flag.String("catalogue", "", "a JSON file holding the catalogue to serve (defaults to a built-in catalogue)")
+	tmdbKey := flag.String("tmdb-key", "", "the TMDB API key to accept (defaults to any key)")
+	omdbKey := flag.String("omdb-key", "", "the OMDB API key to accept (defaults to any key)")
+	tmdbPageSize := flag.Int("tmdb-page-size", 20, "the number of results per TMDB page")
+	omdbPageSize := flag.Int("omdb-page-size", 10, "the number of results per OMDB page")
+	flag.Parse()
+
+	config := searchtest.FakeProviderConfig{
+		TmdbAPIKey:   *tmdbKey,
+		OmdbAPIKey:   *omdbKey,
+		TmdbPageSize: *tmdbPageSize,
+		OmdbPageSize: *omdbPageSize,
+	}
+
+	if *cataloguePath != "" {
+		data, err := os.ReadFile(*cataloguePath)
+		if err != nil {
+			log.Fatalf("Failed to read catalogue: %v", err)
+		}
+
+		var catalogue []search.SearchResult
+		if err := json.Unmarshal(data, &catalogue); err != nil {
+			log.Fatalf("Failed to parse catalogue: %v", err)
+		}
+
+		config.Catalogue = catalogue
+	}
+
+	log.Printf("Serving fake TMDB and OMDB APIs on %s\n", *addr)
+	log.Fatal(http.ListenAndServe(*addr, searchtest.NewFakeProvider(config)))
+}
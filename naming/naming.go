@@ -0,0 +1,243 @@
+// Package naming renders search results into the folder and file names media servers such as Plex, Jellyfin,
+// and Kodi use to identify titles, e.g. "The Matrix (1999) {imdb-tt0133093}".
+package naming
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// Config holds the templates and target operating system of a Formatter.
+//
+// Templates are text/template templates executed with a Data value. A "/" in a template separates path segments,
+// which are joined with the target operating system's path separator; fields are sanitized before rendering,
+// so a "/" in a title never introduces a new segment.
+type Config struct {
+	// Movie is the template used for movies and episodes. Defaults to the Plex convention.
+	Movie string
+	// Series is the template used for series. Defaults to the Plex convention.
+	Series string
+	// Episode is the template used for the episodes of a series. Defaults to the Plex convention.
+	Episode string
+	// OS is the GOOS value of the operating system names are sanitized for (e.g. "windows"). Defaults to runtime.GOOS.
+	OS string
+}
+
+// Plex follows Plex's naming conventions, identifying titles by IMDb ID.
+var Plex = Config{
+	Movie:   `{{.Title}}{{with .Year}} ({{.}}){{end}}{{with .ImdbID}} {imdb-{{.}}}{{end}}`,
+	Series:  `{{.Title}}{{with .Year}} ({{.}}){{end}}{{with .ImdbID}} {imdb-{{.}}}{{end}}`,
+	Episode: `{{.Title}}{{with .Year}} ({{.}}){{end}}{{with .ImdbID}} {imdb-{{.}}}{{end}}/Season {{printf "%02d" .Season}}/{{.Title}} - S{{printf "%02d" .Season}}E{{printf "%02d" .Episode}}`,
+}
+
+// Jellyfin follows Jellyfin's naming conventions, identifying titles by TMDB ID.
+var Jellyfin = Config{
+	Movie:   `{{.Title}}{{with .Year}} ({{.}}){{end}}{{with .TmdbID}} [tmdbid-{{.}}]{{end}}`,
+	Series:  `{{.Title}}{{with .Year}} ({{.}}){{end}}{{with .TmdbID}} [tmdbid-{{.}}]{{end}}`,
+	Episode: `{{.Title}}{{with .Year}} ({{.}}){{end}}{{with .TmdbID}} [tmdbid-{{.}}]{{end}}/Season {{printf "%02d" .Season}}/{{.Title}} S{{printf "%02d" .Season}}E{{printf "%02d" .Episode}}`,
+}
+
+// Kodi follows Kodi's naming conventions, which rely on NFO files rather than names to identify titles.
+var Kodi = Config{
+	Movie:   `{{.Title}}{{with .Year}} ({{.}}){{end}}`,
+	Series:  `{{.Title}}{{with .Year}} ({{.}}){{end}}`,
+	Episode: `{{.Title}}{{with .Year}} ({{.}}){{end}}/Season {{.Season}}/{{.Title}} S{{printf "%02d" .Season}}E{{printf "%02d" .Episode}}`,
+}
+
+// Data is the data templates are executed with.
+type Data struct {
+	// Title is the sanitized title.
+	Title string
+	// Year is the release year, or the year a series started, or empty if unknown.
+	Year string
+	// ImdbID is the IMDb ID (e.g. "tt0133093"), or empty if unknown.
+	ImdbID string
+	// TmdbID is the TMDB ID (e.g. "603"), or empty if unknown.
+	TmdbID string
+	// Type is the type of the title.
+	Type search.ResultType
+	// Season is the season number, when formatting an episode.
+	Season int
+	// Episode is the episode number, when formatting an episode.
+	Episode int
+}
+
+// A Formatter renders search results into names.
+type Formatter struct {
+	movie   *template.Template
+	series  *template.Template
+	episode *template.Template
+	os      string
+}
+
+// NewFormatter creates a new Formatter.
+//
+// Parameters:
+//   - config: The templates and target operating system of the Formatter.
+//
+// Returns:
+//   - *Formatter: A new instance of Formatter.
+//   - error: An error if a template cannot be parsed.
+func NewFormatter(config Config) (*Formatter, error) {
+	if config.OS == "" {
+		config.OS = runtime.GOOS
+	}
+
+	formatter := &Formatter{os: config.OS}
+
+	for _, t := range []struct {
+		name     string
+		text     string
+		fallback string
+		dest     **template.Template
+	}{
+		{"movie", config.Movie, Plex.Movie, &formatter.movie},
+		{"series", config.Series, Plex.Series, &formatter.series},
+		{"episode", config.Episode, Plex.Episode, &formatter.episode},
+	} {
+		if t.text == "" {
+			t.text = t.fallback
+		}
+
+		parsed, err := template.New(t.name).Parse(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s template: %w", t.name, err)
+		}
+
+		*t.dest = parsed
+	}
+
+	return formatter, nil
+}
+
+// Format renders the name of a movie or series folder.
+//
+// Parameters:
+//   - result: The title to render; series use the Series template, and movies and episodes the Movie template.
+//
+// Returns:
+//   - string: The sanitized name.
+//   - error: An error if the template cannot be executed.
+func (f *Formatter) Format(result search.SearchResult) (string, error) {
+	if result.Type == search.Series {
+		return f.render(f.series, f.data(result))
+	}
+
+	return f.render(f.movie, f.data(result))
+}
+
+// FormatEpisode renders the path of an episode of a series, relative to the series' parent folder.
+//
+// Parameters:
+//   - series: The series the episode belongs to.
+//   - season: The season number.
+//   - episode: The episode number.
+//
+// Returns:
+//   - string: The sanitized path, without a file extension.
+//   - error: An error if the template cannot be executed.
+func (f *Formatter) FormatEpisode(series search.SearchResult, season, episode int) (string, error) {
+	data := f.data(series)
+	data.Season, data.Episode = season, episode
+
+	return f.render(f.episode, data)
+}
+
+// data returns the template data of a result.
+func (f *Formatter) data(result search.SearchResult) Data {
+	return Data{
+		Title:  Sanitize(result.Title, f.os),
+		Year:   result.StartYear(),
+		ImdbID: Sanitize(result.ImdbID, f.os),
+		TmdbID: Sanitize(result.TmdbID, f.os),
+		Type:   result.Type,
+	}
+}
+
+// render executes a template and sanitizes each segment of the resulting path.
+func (f *Formatter) render(t *template.Template, data Data) (string, error) {
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to execute %s template: %w", t.Name(), err)
+	}
+
+	segments := strings.Split(sb.String(), "/")
+	for i, segment := range segments {
+		segments[i] = Sanitize(segment, f.os)
+	}
+
+	separator := "/"
+	if f.os == "windows" {
+		separator = `\`
+	}
+
+	return strings.Join(segments, separator), nil
+}
+
+// windowsReserved are the device names Windows doesn't allow as file names, with or without an extension.
+var windowsReserved = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// Sanitize makes a single file or folder name valid on an operating system.
+// Path separators become dashes, and colons (which Windows and macOS reject) become " -" so that
+// "Mission: Impossible" reads "Mission - Impossible". Other invalid characters are dropped,
+// and runs of whitespace are collapsed.
+//
+// Parameters:
+//   - name: The name to sanitize.
+//   - goos: The GOOS value of the target operating system (e.g. "windows", "darwin", or "linux").
+//
+// Returns:
+//   - string: The sanitized name.
+func Sanitize(name, goos string) string {
+	var sb strings.Builder
+
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '/' || (r == '\\' && goos == "windows"):
+			sb.WriteRune('-')
+		case r == ':' && (goos == "windows" || goos == "darwin"):
+			if i+1 < len(runes) && unicode.IsSpace(runes[i+1]) {
+				sb.WriteString(" -")
+			} else {
+				sb.WriteRune('-')
+			}
+		case r == '"' && goos == "windows":
+			sb.WriteRune('\'')
+		case strings.ContainsRune("<>|?*", r) && goos == "windows":
+		case unicode.IsSpace(r):
+			sb.WriteRune(' ')
+		case unicode.IsControl(r):
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	sanitized := strings.Join(strings.Fields(sb.String()), " ")
+
+	if goos == "windows" {
+		// Windows silently strips trailing dots and spaces, which would make names collide
+		sanitized = strings.TrimRight(sanitized, ". ")
+
+		base, _, _ := strings.Cut(sanitized, ".")
+		if windowsReserved[strings.ToUpper(base)] {
+			sanitized = "_" + sanitized
+		}
+	}
+
+	// "." and ".." refer to directories
+	if strings.Trim(sanitized, ".") == "" {
+		sanitized = strings.ReplaceAll(sanitized, ".", "_")
+	}
+
+	return sanitized
+}
@@ -0,0 +1,140 @@
+package naming_test
+
+import (
+	"testing"
+
+	"github.com/jdahan/gogettitles/naming"
+	"github.com/jdahan/gogettitles/search"
+)
+
+var (
+	matrix = search.SearchResult{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093", TmdbID: "603", ProviderId: "603", Type: search.Movie}
+	office = search.SearchResult{Title: "The Office", Year: "2005", TmdbID: "2316", ProviderId: "2316", Type: search.Series}
+)
+
+func newFormatter(t *testing.T, config naming.Config, goos string) *naming.Formatter {
+	t.Helper()
+
+	config.OS = goos
+	formatter, err := naming.NewFormatter(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return formatter
+}
+
+func TestFormatter_Format(t *testing.T) {
+	tests := []struct {
+		name   string
+		config naming.Config
+		result search.SearchResult
+		want   string
+	}{
+		{"plex movie", naming.Plex, matrix, "The Matrix (1999) {imdb-tt0133093}"},
+		{"jellyfin movie", naming.Jellyfin, matrix, "The Matrix (1999) [tmdbid-603]"},
+		{"kodi movie", naming.Kodi, matrix, "The Matrix (1999)"},
+		{"jellyfin series", naming.Jellyfin, office, "The Office (2005) [tmdbid-2316]"},
+		{"plex without imdb id", naming.Plex, office, "The Office (2005)"},
+		{
+			"omdb series",
+			naming.Plex,
+			search.SearchResult{Title: "Breaking Bad", Year: "2008–2013", ImdbID: "tt0903747", Type: search.Series},
+			"Breaking Bad (2008) {imdb-tt0903747}",
+		},
+		{"without year", naming.Plex, search.SearchResult{Title: "Untitled", Type: search.Movie}, "Untitled"},
+		{"default config", naming.Config{}, matrix, "The Matrix (1999) {imdb-tt0133093}"},
+		{
+			"custom template",
+			naming.Config{Movie: `{{.Year}} - {{.Title}} [tmdb-{{.TmdbID}}]`},
+			matrix,
+			"1999 - The Matrix [tmdb-603]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newFormatter(t, tt.config, "linux").Format(tt.result)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatter_FormatEpisode(t *testing.T) {
+	tests := []struct {
+		name   string
+		config naming.Config
+		goos   string
+		want   string
+	}{
+		{"plex", naming.Plex, "linux", "The Office (2005)/Season 02/The Office - S02E05"},
+		{"jellyfin", naming.Jellyfin, "linux", "The Office (2005) [tmdbid-2316]/Season 02/The Office S02E05"},
+		{"windows separators", naming.Jellyfin, "windows", `The Office (2005) [tmdbid-2316]\Season 02\The Office S02E05`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newFormatter(t, tt.config, tt.goos).FormatEpisode(office, 2, 5)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatter_Format_SanitizesTitle(t *testing.T) {
+	result := search.SearchResult{Title: "AC/DC: Let There Be Rock", Year: "1980", ImdbID: "tt0081051", Type: search.Movie}
+
+	got, err := newFormatter(t, naming.Plex, "windows").Format(result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := "AC-DC - Let There Be Rock (1980) {imdb-tt0081051}"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestNewFormatter_InvalidTemplate(t *testing.T) {
+	if _, err := naming.NewFormatter(naming.Config{Movie: "{{.Title"}); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		want string
+	}{
+		{"Mission: Impossible", "windows", "Mission - Impossible"},
+		{"Mission: Impossible", "darwin", "Mission - Impossible"},
+		{"Mission: Impossible", "linux", "Mission: Impossible"},
+		{`What's "Up"?`, "windows", "What's 'Up'"},
+		{`What's "Up"?`, "linux", `What's "Up"?`},
+		{"Face/Off", "linux", "Face-Off"},
+		{`Black\White`, "windows", "Black-White"},
+		{"Se7en...", "windows", "Se7en"},
+		{"Se7en...", "linux", "Se7en..."},
+		{"CON", "windows", "_CON"},
+		{"Con Air", "windows", "Con Air"},
+		{"..", "linux", "__"},
+		{"  Tabs\tand\nnewlines  ", "linux", "Tabs and newlines"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos+"/"+tt.name, func(t *testing.T) {
+			if got := naming.Sanitize(tt.name, tt.goos); got != tt.want {
+				t.Errorf("Sanitize(%q, %q) = %q, want %q", tt.name, tt.goos, got, tt.want)
+			}
+		})
+	}
+}
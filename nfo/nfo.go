@@ -0,0 +1,222 @@
+// Package nfo generates the .nfo XML sidecar files Kodi and Jellyfin read a title's metadata from.
+package nfo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// A Document is a movie or tvshow NFO document.
+type Document struct {
+	// XMLName is the root element, "movie" or "tvshow".
+	XMLName       xml.Name
+	Title         string     `xml:"title"`
+	OriginalTitle string     `xml:"originaltitle,omitempty"`
+	Ratings       *Ratings   `xml:"ratings,omitempty"`
+	Plot          string     `xml:"plot,omitempty"`
+	Runtime       int        `xml:"runtime,omitempty"`
+	Thumbs        []Thumb    `xml:"thumb"`
+	Mpaa          string     `xml:"mpaa,omitempty"`
+	UniqueIDs     []UniqueID `xml:"uniqueid"`
+	Genres        []string   `xml:"genre"`
+	Countries     []string   `xml:"country"`
+	Directors     []string   `xml:"director"`
+	Year          string     `xml:"year,omitempty"`
+	Actors        []Actor    `xml:"actor"`
+}
+
+// Ratings holds the ratings of a title.
+type Ratings struct {
+	Ratings []Rating `xml:"rating"`
+}
+
+// A Rating is a single score awarded to a title.
+type Rating struct {
+	// Name identifies the rating source, e.g. "imdb" or "metacritic".
+	Name    string  `xml:"name,attr"`
+	Max     int     `xml:"max,attr"`
+	Default bool    `xml:"default,attr,omitempty"`
+	Value   float64 `xml:"value"`
+}
+
+// A Thumb is an artwork URL.
+type Thumb struct {
+	// Aspect is the kind of artwork, e.g. "poster".
+	Aspect string `xml:"aspect,attr,omitempty"`
+	URL    string `xml:",chardata"`
+}
+
+// A UniqueID identifies a title in an external database.
+type UniqueID struct {
+	// Type is the database, "imdb" or "tmdb".
+	Type string `xml:"type,attr"`
+	// Default marks the ID scrapers should prefer.
+	Default bool   `xml:"default,attr,omitempty"`
+	ID      string `xml:",chardata"`
+}
+
+// An Actor is a cast member.
+type Actor struct {
+	Name  string `xml:"name"`
+	Order int    `xml:"order"`
+}
+
+// ratingSources maps the rating sources reported by providers to NFO rating names and maximum values.
+var ratingSources = map[string]struct {
+	name string
+	max  int
+}{
+	"Internet Movie Database": {"imdb", 10},
+	"Rotten Tomatoes":         {"tomatometerallcritics", 100},
+	"Metacritic":              {"metacritic", 100},
+	"The Movie Database":      {"themoviedb", 10},
+}
+
+// UnsupportedTypeError is an error type that is returned when no NFO document exists for a title's type.
+type UnsupportedTypeError struct {
+	resultType search.ResultType
+}
+
+// NewUnsupportedTypeError creates a new UnsupportedTypeError for the specified type.
+func NewUnsupportedTypeError(resultType search.ResultType) *UnsupportedTypeError {
+	return &UnsupportedTypeError{resultType: resultType}
+}
+
+// Error returns the error message associated with the UnsupportedTypeError.
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("no NFO document for titles of type %q", e.resultType)
+}
+
+// New creates an NFO document from the details of a title. Series are dated by the year they started,
+// since scrapers expect a single year rather than a run such as OMDB's "2008–2013".
+//
+// Parameters:
+//   - details: The details of a movie or series, as returned by a search.Lookuper.
+//
+// Returns:
+//   - *Document: A movie document for movies, or a tvshow document for series.
+//   - error: An UnsupportedTypeError if the title is neither a movie nor a series.
+func New(details *search.TitleDetails) (*Document, error) {
+	var root string
+	switch details.Type {
+	case search.Movie:
+		root = "movie"
+	case search.Series:
+		root = "tvshow"
+	default:
+		return nil, NewUnsupportedTypeError(details.Type)
+	}
+
+	doc := &Document{
+		XMLName:   xml.Name{Local: root},
+		Title:     details.Title,
+		Plot:      details.Plot,
+		Runtime:   details.Runtime,
+		Mpaa:      details.Rated,
+		Genres:    details.Genres,
+		Countries: details.Countries,
+		Directors: details.Directors,
+		Year:      details.StartYear(),
+	}
+
+	if details.OriginalTitle != details.Title {
+		doc.OriginalTitle = details.OriginalTitle
+	}
+
+	if details.PosterURL != "" {
+		doc.Thumbs = []Thumb{{Aspect: "poster", URL: details.PosterURL}}
+	}
+
+	// Scrapers prefer the IMDb ID, which every provider understands
+	if details.ImdbID != "" {
+		doc.UniqueIDs = append(doc.UniqueIDs, UniqueID{Type: "imdb", Default: true, ID: details.ImdbID})
+	}
+
+	if details.TmdbID != "" {
+		doc.UniqueIDs = append(doc.UniqueIDs, UniqueID{Type: "tmdb", Default: details.ImdbID == "", ID: details.TmdbID})
+	}
+
+	for i, name := range details.Cast {
+		doc.Actors = append(doc.Actors, Actor{Name: name, Order: i})
+	}
+
+	for _, rating := range details.Ratings {
+		source, ok := ratingSources[rating.Source]
+		if !ok {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimSuffix(strings.Split(rating.Value, "/")[0], "%"), 64)
+		if err != nil {
+			continue
+		}
+
+		if doc.Ratings == nil {
+			doc.Ratings = &Ratings{}
+		}
+
+		doc.Ratings.Ratings = append(doc.Ratings.Ratings, Rating{
+			Name:    source.name,
+			Max:     source.max,
+			Default: len(doc.Ratings.Ratings) == 0,
+			Value:   value,
+		})
+	}
+
+	return doc, nil
+}
+
+// NewFromResult creates an NFO document from a search result, for titles whose details haven't been looked up.
+//
+// Parameters:
+//   - result: A movie or series search result.
+//
+// Returns:
+//   - *Document: A movie document for movies, or a tvshow document for series.
+//   - error: An UnsupportedTypeError if the title is neither a movie nor a series.
+func NewFromResult(result search.SearchResult) (*Document, error) {
+	return New(&search.TitleDetails{SearchResult: result})
+}
+
+// Marshal encodes the document as indented XML, with an XML declaration.
+//
+// Returns:
+//   - []byte: The encoded document.
+//   - error: An error if the document cannot be encoded.
+func (d *Document) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.Write(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Write encodes the document as indented XML, with an XML declaration, to a writer.
+//
+// Parameters:
+//   - w: The writer to write the document to.
+//
+// Returns:
+//   - error: An error if the document cannot be encoded or written.
+func (d *Document) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n"); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(d); err != nil {
+		return fmt.Errorf("failed to encode NFO document: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
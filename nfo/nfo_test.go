@@ -0,0 +1,350 @@
+package nfo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/jdahan/gogettitles/nfo"
+	"github.com/jdahan/gogettitles/search"
+)
+
+const testAPIKey = "testkey"
+
+// lookup returns the details a TmdbSearcher looks up from a TMDB details response in the search package's test data.
+func lookup(t *testing.T, fixture, path, id string, resultType search.ResultType) *search.TitleDetails {
+	t.Helper()
+	defer gock.Off() // Flush pending mocks after test execution
+
+	mockData, err := os.ReadFile("../search/testdata/" + fixture)
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://api.themoviedb.org").
+		Get(path).
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	details, err := search.NewTmdbSearcher(testAPIKey, http.DefaultClient).LookupByProviderID(context.Background(), id, resultType)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return details
+}
+
+func matrix(t *testing.T) *search.TitleDetails {
+	return lookup(t, "tmdb_movie_details_response.json", "/3/movie/603", "603", search.Movie)
+}
+
+func breakingBad(t *testing.T) *search.TitleDetails {
+	return lookup(t, "tmdb_tv_details_response.json", "/3/tv/1396", "1396", search.Series)
+}
+
+// omdbBreakingBad returns the details an OmdbSearcher looks up for a series, whose year is the run of the series.
+func omdbBreakingBad(t *testing.T) *search.TitleDetails {
+	t.Helper()
+	defer gock.Off() // Flush pending mocks after test execution
+
+	mockData, err := os.ReadFile("../search/testdata/omdb_series_lookup_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("i", "tt0903747").
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	details, err := search.NewOmdbSearcher(testAPIKey, http.DefaultClient).LookupByImdbID(context.Background(), "tt0903747")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return details
+}
+
+func marshal(t *testing.T, details *search.TitleDetails) []byte {
+	t.Helper()
+
+	doc, err := nfo.New(details)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := doc.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return data
+}
+
+func TestDocument_Marshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		details *search.TitleDetails
+		golden  string
+	}{
+		{"movie", matrix(t), "testdata/movie.nfo"},
+		{"tvshow", breakingBad(t), "testdata/tvshow.nfo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := os.ReadFile(tt.golden)
+			if err != nil {
+				t.Fatalf("unexpected error reading golden file: %v", err)
+			}
+
+			if got := marshal(t, tt.details); !bytes.Equal(got, want) {
+				t.Errorf("unexpected document:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestNewFromResult(t *testing.T) {
+	doc, err := nfo.NewFromResult(matrix(t).SearchResult)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if doc.Title != "The Matrix" || doc.Year != "1999" || len(doc.UniqueIDs) != 2 || len(doc.Thumbs) != 1 || doc.Plot != "" {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+}
+
+func TestNew_Thumb(t *testing.T) {
+	doc, err := nfo.New(matrix(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// TMDB reports poster paths relative to its image server, which scrapers can't fetch
+	want := "https://image.tmdb.org/t/p/original/p96dm7sCMn4VYAStA6siNz30G1r.jpg"
+	if len(doc.Thumbs) != 1 || doc.Thumbs[0].URL != want {
+		t.Errorf("expected poster %q, got %+v", want, doc.Thumbs)
+	}
+}
+
+func TestNew_Ratings(t *testing.T) {
+	details := matrix(t)
+	details.Ratings = append(details.Ratings,
+		search.Rating{Source: "Internet Movie Database", Value: "8.7/10"},
+		search.Rating{Source: "Rotten Tomatoes", Value: "83%"},
+		search.Rating{Source: "Metacritic", Value: "73/100"},
+		search.Rating{Source: "Letterboxd", Value: "4.2/5"},
+	)
+
+	doc, err := nfo.New(details)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// TMDB's own rating comes first, and unknown sources are dropped
+	want := []nfo.Rating{
+		{Name: "themoviedb", Max: 10, Default: true, Value: 8.2},
+		{Name: "imdb", Max: 10, Value: 8.7},
+		{Name: "tomatometerallcritics", Max: 100, Value: 83},
+		{Name: "metacritic", Max: 100, Value: 73},
+	}
+	if doc.Ratings == nil || !reflect.DeepEqual(doc.Ratings.Ratings, want) {
+		t.Errorf("expected ratings %+v, got %+v", want, doc.Ratings)
+	}
+}
+
+func TestNew_UnsupportedType(t *testing.T) {
+	_, err := nfo.NewFromResult(search.SearchResult{Title: "Pilot", Type: search.Episode})
+
+	var unsupported *nfo.UnsupportedTypeError
+	if !errors.As(err, &unsupported) {
+		t.Errorf("expected UnsupportedTypeError, got %v", err)
+	}
+}
+
+// element is a generic XML element, used to check documents against the schema independently of nfo.Document.
+type element struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []element  `xml:",any"`
+	Text     string     `xml:",chardata"`
+}
+
+func (e element) attr(name string) (string, bool) {
+	for _, attr := range e.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// schemaElement describes an element allowed by the Kodi NFO schema.
+type schemaElement struct {
+	// repeated reports whether the element may occur more than once.
+	repeated bool
+	// pattern, if set, is the pattern the element's text must match.
+	pattern *regexp.Regexp
+	// attrs are the allowed attributes and the patterns their values must match.
+	attrs map[string]*regexp.Regexp
+	// children are the allowed child elements; elements without children hold text.
+	children map[string]schemaElement
+}
+
+var (
+	text      = regexp.MustCompile(`\S`)
+	integer   = regexp.MustCompile(`^\d+$`)
+	decimal   = regexp.MustCompile(`^\d+(\.\d+)?$`)
+	boolean   = regexp.MustCompile(`^(true|false)$`)
+	yearValue = regexp.MustCompile(`^(19|20)\d{2}$`)
+	url       = regexp.MustCompile(`^https?://\S+$`)
+)
+
+// nfoSchema lists the elements Kodi and Jellyfin read from movie and tvshow documents, as documented at
+// https://kodi.wiki/view/NFO_files/Movies and https://kodi.wiki/view/NFO_files/TV_shows.
+var nfoSchema = map[string]schemaElement{
+	"title":         {pattern: text},
+	"originaltitle": {pattern: text},
+	"ratings": {children: map[string]schemaElement{
+		"rating": {
+			repeated: true,
+			attrs:    map[string]*regexp.Regexp{"name": regexp.MustCompile(`^(imdb|themoviedb|metacritic|tomatometerallcritics|trakt)$`), "max": integer, "default": boolean},
+			children: map[string]schemaElement{"value": {pattern: decimal}, "votes": {pattern: integer}},
+		},
+	}},
+	"plot":     {pattern: text},
+	"runtime":  {pattern: integer},
+	"thumb":    {repeated: true, pattern: url, attrs: map[string]*regexp.Regexp{"aspect": regexp.MustCompile(`^(poster|banner|clearart|clearlogo|landscape|keyart)$`)}},
+	"mpaa":     {pattern: text},
+	"uniqueid": {repeated: true, pattern: text, attrs: map[string]*regexp.Regexp{"type": regexp.MustCompile(`^(imdb|tmdb|tvdb)$`), "default": boolean}},
+	"genre":    {repeated: true, pattern: text},
+	"country":  {repeated: true, pattern: text},
+	"director": {repeated: true, pattern: text},
+	"year":     {pattern: yearValue},
+	"actor": {repeated: true, children: map[string]schemaElement{
+		"name": {pattern: text}, "role": {pattern: text}, "order": {pattern: integer}, "thumb": {pattern: url},
+	}},
+}
+
+// validate checks the children of an element against the schema.
+func validate(t *testing.T, path string, parent element, schema map[string]schemaElement) {
+	t.Helper()
+
+	counts := map[string]int{}
+	for _, child := range parent.Children {
+		name := child.XMLName.Local
+		childPath := path + "/" + name
+		counts[name]++
+
+		spec, ok := schema[name]
+		if !ok {
+			t.Errorf("%s: element not allowed by the schema", childPath)
+			continue
+		}
+		if counts[name] > 1 && !spec.repeated {
+			t.Errorf("%s: element may only occur once", childPath)
+		}
+
+		for _, attr := range child.Attrs {
+			pattern, ok := spec.attrs[attr.Name.Local]
+			if !ok {
+				t.Errorf("%s: attribute %q not allowed by the schema", childPath, attr.Name.Local)
+			} else if !pattern.MatchString(attr.Value) {
+				t.Errorf("%s: attribute %q has invalid value %q", childPath, attr.Name.Local, attr.Value)
+			}
+		}
+
+		if spec.children != nil {
+			validate(t, childPath, child, spec.children)
+		} else if len(child.Children) > 0 {
+			t.Errorf("%s: element may only hold text", childPath)
+		} else if spec.pattern != nil && !spec.pattern.MatchString(child.Text) {
+			t.Errorf("%s: invalid value %q", childPath, child.Text)
+		}
+	}
+}
+
+func TestDocument_SchemaConformance(t *testing.T) {
+	tests := []struct {
+		name    string
+		details *search.TitleDetails
+		root    string
+	}{
+		{"movie", matrix(t), "movie"},
+		{"tvshow", breakingBad(t), "tvshow"},
+		{"tvshow from OMDB", omdbBreakingBad(t), "tvshow"},
+		{"movie from search result", &search.TitleDetails{SearchResult: matrix(t).SearchResult}, "movie"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := marshal(t, tt.details)
+
+			if !bytes.HasPrefix(data, []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)) {
+				t.Errorf("expected an XML declaration, got %q", data[:min(len(data), 60)])
+			}
+
+			var root element
+			decoder := xml.NewDecoder(bytes.NewReader(data))
+			decoder.Strict = true
+			if err := decoder.Decode(&root); err != nil {
+				t.Fatalf("document is not well-formed XML: %v", err)
+			}
+			for {
+				token, err := decoder.Token()
+				if err == io.EOF {
+					break
+				}
+				if data, ok := token.(xml.CharData); !ok || len(bytes.TrimSpace(data)) > 0 {
+					t.Fatalf("expected a single root element, got %v, %v", token, err)
+				}
+			}
+
+			if root.XMLName.Local != tt.root {
+				t.Fatalf("expected root element %q, got %q", tt.root, root.XMLName.Local)
+			}
+
+			validate(t, "/"+tt.root, root, nfoSchema)
+
+			var titles, defaults, actors int
+			for _, child := range root.Children {
+				switch child.XMLName.Local {
+				case "title":
+					titles++
+				case "uniqueid":
+					if value, _ := child.attr("default"); value == "true" {
+						defaults++
+					}
+				case "actor":
+					for _, field := range child.Children {
+						if field.XMLName.Local == "order" && field.Text != strconv.Itoa(actors) {
+							t.Errorf("expected actor order %d, got %q", actors, field.Text)
+						}
+					}
+					actors++
+				}
+			}
+
+			if titles != 1 {
+				t.Errorf("expected exactly one title, got %d", titles)
+			}
+			if defaults != 1 {
+				t.Errorf("expected exactly one default uniqueid, got %d", defaults)
+			}
+		})
+	}
+}
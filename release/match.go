@@ -0,0 +1,46 @@
+package release
+
+import (
+	"context"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// A Match is a search result matched to a release filename.
+type Match struct {
+	// Release is the release parsed from the filename.
+	Release Release
+	search.Resolution
+}
+
+// MatchFile parses a media filename and resolves the title it contains.
+// The parsed title is used as the query, with the parsed year and type (series for episodic releases) as hints.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - resolver: The Resolver to resolve the title with.
+//   - name: The filename or path to match.
+//
+// Returns:
+//   - *Match: The best matching search result and its confidence.
+//   - error: A search.NoMatchError if no result matches, a search.AmbiguousMatchError if the best results
+//     are too close to call, or an error if the search operation fails.
+func MatchFile(ctx context.Context, resolver *search.Resolver, name string) (*Match, error) {
+	release := Parse(name)
+	if release.Title == "" {
+		return nil, search.NewNoMatchError(name)
+	}
+
+	hints := search.Hints{Year: release.Year}
+	if release.IsEpisodic() {
+		// Episodes name the year the series started, if any, which tells remakes such as Doctor Who (2005) apart
+		hints.Type = search.Series
+	}
+
+	resolution, err := resolver.Resolve(ctx, release.Title, hints)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Match{Release: release, Resolution: *resolution}, nil
+}
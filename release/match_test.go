@@ -0,0 +1,124 @@
+package release_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jdahan/gogettitles/release"
+	"github.com/jdahan/gogettitles/search"
+)
+
+// catalogueSearcher returns the titles in its catalogue containing the query that satisfy the Year and Type search options.
+type catalogueSearcher struct {
+	catalogue []search.SearchResult
+	queries   []search.SearchOptions
+}
+
+func (cs *catalogueSearcher) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	opts := search.SearchOptionsFromContext(ctx)
+	cs.queries = append(cs.queries, opts)
+
+	var results []search.SearchResult
+	for _, result := range cs.catalogue {
+		if !strings.Contains(strings.ToLower(result.Title), strings.ToLower(query)) {
+			continue
+		}
+
+		if (opts.Year == "" || result.Year == opts.Year) && (opts.Type == "" || result.Type == opts.Type) {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+var catalogue = []search.SearchResult{
+	{Title: "The Matrix Reloaded", Year: "2003", Type: search.Movie, ImdbID: "tt0234215"},
+	{Title: "The Matrix", Year: "1999", Type: search.Movie, ImdbID: "tt0133093"},
+	{Title: "The Matrix", Year: "1993", Type: search.Series, ImdbID: "tt0106062"},
+	{Title: "Breaking Bad", Year: "2008", Type: search.Series, ImdbID: "tt0903747"},
+	{Title: "Heat", Year: "1995", Type: search.Movie, ImdbID: "tt0113277"},
+}
+
+func TestMatchFile_Movie(t *testing.T) {
+	searcher := &catalogueSearcher{catalogue: catalogue}
+
+	match, err := release.MatchFile(context.Background(), search.NewResolver(searcher, search.ResolverConfig{}), "The.Matrix.1999.1080p.BluRay.x264-GRP.mkv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if match.Result.ImdbID != "tt0133093" {
+		t.Errorf("expected The Matrix (1999), got %+v", match.Result)
+	}
+	if match.Confidence < 0.99 {
+		t.Errorf("expected an exact match, got confidence %.2f", match.Confidence)
+	}
+	if opts := searcher.queries[0]; opts.Year != "1999" || opts.Type != "" {
+		t.Errorf("expected the year to be used as a filter, got %+v", opts)
+	}
+}
+
+func TestMatchFile_Episode(t *testing.T) {
+	searcher := &catalogueSearcher{catalogue: catalogue}
+
+	match, err := release.MatchFile(context.Background(), search.NewResolver(searcher, search.ResolverConfig{}), "Breaking.Bad.S02E05.720p.HDTV.x264-CTU.mkv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if match.Result.ImdbID != "tt0903747" || match.Release.Season != 2 || match.Release.Episode != 5 {
+		t.Errorf("expected Breaking Bad S02E05, got %+v", match)
+	}
+	if opts := searcher.queries[0]; opts.Type != search.Series {
+		t.Errorf("expected episodic releases to search series, got %+v", opts)
+	}
+}
+
+func TestMatchFile_EpisodeYear(t *testing.T) {
+	searcher := &catalogueSearcher{catalogue: []search.SearchResult{
+		{Title: "Doctor Who", Year: "1963", Type: search.Series, ImdbID: "tt0056751"},
+		{Title: "Doctor Who", Year: "2005", Type: search.Series, ImdbID: "tt0436992"},
+	}}
+
+	match, err := release.MatchFile(context.Background(), search.NewResolver(searcher, search.ResolverConfig{}), "Doctor.Who.2005.S01E01.720p.BluRay.x264-GRP.mkv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if match.Result.ImdbID != "tt0436992" || match.Release.Season != 1 || match.Release.Episode != 1 {
+		t.Errorf("expected Doctor Who (2005) S01E01, got %+v", match)
+	}
+	if opts := searcher.queries[0]; opts.Year != "2005" || opts.Type != search.Series {
+		t.Errorf("expected the series start year to be used as a filter, got %+v", opts)
+	}
+}
+
+func TestMatchFile_WrongYearRetries(t *testing.T) {
+	searcher := &catalogueSearcher{catalogue: catalogue}
+
+	match, err := release.MatchFile(context.Background(), search.NewResolver(searcher, search.ResolverConfig{}), "Heat.1996.DVDRip.avi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(searcher.queries) != 2 || searcher.queries[1].Year != "" {
+		t.Errorf("expected a retry without the year, got %+v", searcher.queries)
+	}
+	if match.Result.ImdbID != "tt0113277" || match.Confidence >= 1 || match.Confidence < 0.8 {
+		t.Errorf("expected Heat with reduced confidence, got %+v", match)
+	}
+}
+
+func TestMatchFile_NoMatch(t *testing.T) {
+	searcher := &catalogueSearcher{catalogue: catalogue}
+
+	_, err := release.MatchFile(context.Background(), search.NewResolver(searcher, search.ResolverConfig{}), "Amelie.2001.mkv")
+
+	var noMatch *search.NoMatchError
+	if !errors.As(err, &noMatch) {
+		t.Errorf("expected NoMatchError, got %v", err)
+	}
+}
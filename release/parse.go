@@ -0,0 +1,219 @@
+// Package release parses scene and P2P-style media filenames, such as "The.Matrix.1999.1080p.BluRay.x264-GRP.mkv",
+// and matches them to titles using a search.Resolver.
+package release
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Release holds the information parsed from a media filename.
+type Release struct {
+	// Title is the title of the movie or series, with separators replaced by spaces.
+	Title string
+	// Year is the release year, or empty if the filename doesn't include one.
+	Year string
+	// Season is the season number, or zero if the filename doesn't include one.
+	Season int
+	// Episode is the episode number, or zero if the filename doesn't include one.
+	Episode int
+	// Resolution is the vertical resolution (e.g. "1080p"), or empty if the filename doesn't include one.
+	Resolution string
+	// Source is the media source (e.g. "BluRay" or "WEB-DL"), or empty if the filename doesn't include one.
+	Source string
+	// Group is the release group, or empty if the filename doesn't include one.
+	Group string
+}
+
+// IsEpisodic reports whether the release is a series, season, or episode rather than a movie.
+func (r Release) IsEpisodic() bool {
+	return r.Season > 0 || r.Episode > 0
+}
+
+// videoExtensions are the file extensions stripped from filenames before parsing.
+var videoExtensions = map[string]bool{
+	".mkv": true, ".mp4": true, ".avi": true, ".m4v": true, ".mov": true,
+	".wmv": true, ".ts": true, ".m2ts": true, ".webm": true, ".mpg": true,
+}
+
+// sources maps the lowercase spellings of media sources to their canonical names.
+var sources = map[string]string{
+	"bluray": "BluRay", "blu-ray": "BluRay", "bdrip": "BluRay", "brrip": "BluRay", "bdremux": "BluRay",
+	"remux": "BluRay", "web-dl": "WEB-DL", "webdl": "WEB-DL", "web": "WEB-DL", "webrip": "WEBRip",
+	"hdtv": "HDTV", "pdtv": "HDTV", "dvdrip": "DVDRip", "dvd": "DVD", "dvdscr": "DVDScr",
+	"hdrip": "HDRip", "hdcam": "CAM", "cam": "CAM", "telesync": "TS", "hdts": "TS",
+}
+
+// tags are lowercase tokens that mark the end of the title but carry no information we extract.
+var tags = map[string]bool{
+	"x264": true, "x265": true, "h264": true, "h265": true, "hevc": true, "avc": true, "xvid": true, "divx": true,
+	"10bit": true, "hdr": true, "hdr10": true, "dv": true, "sdr": true, "aac": true, "ac3": true, "dts": true,
+	"ddp": true, "dd": true, "atmos": true, "truehd": true, "flac": true, "proper": true, "repack": true,
+	"internal": true, "limited": true, "extended": true, "unrated": true, "remastered": true, "imax": true,
+	"multi": true, "dubbed": true, "subbed": true, "complete": true, "nf": true, "amzn": true, "dsnp": true,
+	"hmax": true, "atvp": true, "hulu": true,
+}
+
+var (
+	// episodePattern matches "S02E05", "S02E05E06", and "S02E05-E06".
+	episodePattern = regexp.MustCompile(`(?i)^s(\d{1,2})e(\d{1,3})(?:-?e\d{1,3})*$`)
+	// crossPattern matches "2x05".
+	crossPattern = regexp.MustCompile(`(?i)^(\d{1,2})x(\d{2,3})$`)
+	// seasonPattern matches "S02".
+	seasonPattern = regexp.MustCompile(`(?i)^s(\d{1,2})$`)
+	// resolutionPattern matches "1080p", "720i", "4K", and "UHD".
+	resolutionPattern = regexp.MustCompile(`(?i)^(\d{3,4})[pi]$|^(4k|uhd)$`)
+	// yearPattern matches years from 1900 to 2099, optionally in parentheses or brackets.
+	yearPattern = regexp.MustCompile(`^[(\[]?((?:19|20)\d{2})[)\]]?$`)
+	// bracketPattern matches bracketed segments such as "[1080p]".
+	bracketPattern = regexp.MustCompile(`\[[^\]]*\]`)
+	// dottedPattern matches tokens spelled with dots that must survive separator replacement, such as "H.264" and "5.1".
+	dottedPattern = regexp.MustCompile(`(?i)\b(h)\.(26[45])\b|\b(\d)\.(\d)\b`)
+	// groupPattern matches a trailing "-GRP" release group.
+	groupPattern = regexp.MustCompile(`-([A-Za-z0-9]+)$`)
+)
+
+// Parse extracts the title, year, season, episode, resolution, source, and group from a media filename.
+// Any directories and video file extension are ignored. Parse never fails; fields it can't find are left empty.
+//
+// Parameters:
+//   - name: The filename or path to parse.
+//
+// Returns:
+//   - Release: The parsed release.
+func Parse(name string) Release {
+	var release Release
+
+	name = filepath.Base(filepath.ToSlash(name))
+	if ext := filepath.Ext(name); videoExtensions[strings.ToLower(ext)] {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	// Anime-style releases lead with a bracketed group, and may bracket other tags
+	if strings.HasPrefix(name, "[") {
+		if end := strings.Index(name, "]"); end > 0 {
+			release.Group = strings.TrimSpace(name[1:end])
+			name = name[end+1:]
+		}
+	}
+
+	var bracketed []string
+	name = bracketPattern.ReplaceAllStringFunc(name, func(segment string) string {
+		bracketed = append(bracketed, strings.Trim(segment, "[]"))
+		return " "
+	})
+
+	name = dottedPattern.ReplaceAllString(name, "$1$2$3$4")
+
+	// Scene-style releases end with "-GRP" following a tag, unless the dash is part of a source like "WEB-DL"
+	if match := groupPattern.FindStringSubmatchIndex(name); match != nil && release.Group == "" {
+		before := strings.FieldsFunc(name[:match[0]], isSeparator)
+		if len(before) > 0 {
+			last := before[len(before)-1]
+			_, isSource := sources[strings.ToLower(last+name[match[0]:])]
+			if !isSource && (&Release{}).parseToken(last, len(before)) {
+				release.Group = name[match[2]:match[3]]
+				name = name[:match[0]]
+			}
+		}
+	}
+
+	tokens := strings.FieldsFunc(name, isSeparator)
+
+	titleEnd := -1
+	for i, token := range tokens {
+		if release.parseToken(token, i) && titleEnd < 0 {
+			titleEnd = i
+		}
+	}
+
+	for _, segment := range bracketed {
+		for _, token := range strings.FieldsFunc(segment, isSeparator) {
+			release.parseToken(token, len(tokens))
+		}
+	}
+
+	if titleEnd < 0 {
+		titleEnd = len(tokens)
+	}
+
+	// Drop dangling separators like the " - " between a series title and its episode
+	title := tokens[:titleEnd]
+	for len(title) > 0 && strings.Trim(title[len(title)-1], "-") == "" {
+		title = title[:len(title)-1]
+	}
+
+	release.Title = strings.Join(title, " ")
+
+	return release
+}
+
+// parseToken extracts any information from a token into the release.
+//
+// Parameters:
+//   - token: The token to parse.
+//   - index: The position of the token in the name; a leading year is assumed to be part of the title (e.g. "1917").
+//
+// Returns:
+//   - bool: Whether the token marks the end of the title.
+func (r *Release) parseToken(token string, index int) bool {
+	lower := strings.ToLower(token)
+
+	if match := yearPattern.FindStringSubmatch(token); match != nil && index > 0 {
+		year, _ := strconv.Atoi(match[1])
+		if year <= time.Now().Year()+1 {
+			if r.Year == "" {
+				r.Year = match[1]
+			}
+
+			return true
+		}
+	}
+
+	if match := episodePattern.FindStringSubmatch(token); match != nil {
+		r.Season, _ = strconv.Atoi(match[1])
+		r.Episode, _ = strconv.Atoi(match[2])
+		return true
+	}
+
+	if match := crossPattern.FindStringSubmatch(token); match != nil {
+		r.Season, _ = strconv.Atoi(match[1])
+		r.Episode, _ = strconv.Atoi(match[2])
+		return true
+	}
+
+	if match := seasonPattern.FindStringSubmatch(token); match != nil && index > 0 {
+		r.Season, _ = strconv.Atoi(match[1])
+		return true
+	}
+
+	if match := resolutionPattern.FindStringSubmatch(token); match != nil {
+		if match[1] != "" {
+			r.Resolution = match[1] + "p"
+		} else {
+			r.Resolution = "2160p"
+		}
+
+		return true
+	}
+
+	if source, ok := sources[lower]; ok {
+		r.Source = source
+		return true
+	}
+
+	return tags[lower] || strings.HasPrefix(lower, "ddp") || strings.HasPrefix(lower, "aac")
+}
+
+// isSeparator reports whether a rune separates the words of a release name.
+func isSeparator(r rune) bool {
+	switch r {
+	case '.', '_', ' ', '(', ')':
+		return true
+	default:
+		return false
+	}
+}
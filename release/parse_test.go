@@ -0,0 +1,80 @@
+package release_test
+
+import (
+	"testing"
+
+	"github.com/jdahan/gogettitles/release"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		want release.Release
+	}{
+		{
+			name: "The.Matrix.1999.1080p.BluRay.x264-GRP.mkv",
+			want: release.Release{Title: "The Matrix", Year: "1999", Resolution: "1080p", Source: "BluRay", Group: "GRP"},
+		},
+		{
+			name: "Breaking Bad S02E05 720p.mkv",
+			want: release.Release{Title: "Breaking Bad", Season: 2, Episode: 5, Resolution: "720p"},
+		},
+		{
+			name: "/media/tv/The.Office.US.S05E14.Stress.Relief.720p.WEB-DL.DD5.1.H.264-NTb.mkv",
+			want: release.Release{Title: "The Office US", Season: 5, Episode: 14, Resolution: "720p", Source: "WEB-DL", Group: "NTb"},
+		},
+		{
+			name: "1917.2019.2160p.UHD.BluRay.REMUX.HDR.HEVC.Atmos-EPSiLON",
+			want: release.Release{Title: "1917", Year: "2019", Resolution: "2160p", Source: "BluRay", Group: "EPSiLON"},
+		},
+		{
+			name: "Blade_Runner_2049_(2017)_4K.mp4",
+			want: release.Release{Title: "Blade Runner 2049", Year: "2017", Resolution: "2160p"},
+		},
+		{
+			name: "Heat (1995).avi",
+			want: release.Release{Title: "Heat", Year: "1995"},
+		},
+		{
+			name: "Spider-Man.2002.DVDRip.XviD.avi",
+			want: release.Release{Title: "Spider-Man", Year: "2002", Source: "DVDRip"},
+		},
+		{
+			name: "Fargo.2x03.HDTV",
+			want: release.Release{Title: "Fargo", Season: 2, Episode: 3, Source: "HDTV"},
+		},
+		{
+			name: "Succession.S03.COMPLETE.1080p.AMZN.WEBRip",
+			want: release.Release{Title: "Succession", Season: 3, Resolution: "1080p", Source: "WEBRip"},
+		},
+		{
+			name: "[SubsPlease] Frieren - S01E12 [1080p].mkv",
+			want: release.Release{Title: "Frieren", Season: 1, Episode: 12, Resolution: "1080p", Group: "SubsPlease"},
+		},
+		{
+			name: "Some.Show.S01E01.WEB-DL",
+			want: release.Release{Title: "Some Show", Season: 1, Episode: 1, Source: "WEB-DL"},
+		},
+		{
+			name: "Amelie",
+			want: release.Release{Title: "Amelie"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := release.Parse(tt.name); got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelease_IsEpisodic(t *testing.T) {
+	if release.Parse("The.Matrix.1999.mkv").IsEpisodic() {
+		t.Error("expected movie not to be episodic")
+	}
+	if !release.Parse("Breaking.Bad.S01.mkv").IsEpisodic() {
+		t.Error("expected season pack to be episodic")
+	}
+}
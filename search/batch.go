@@ -0,0 +1,327 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// BatchOptions holds the options of a SearchBatch or RunBatch call.
+type BatchOptions struct {
+	// MaxResults is the maximum number of results returned for each query. Defaults to 10. Ignored by RunBatch.
+	MaxResults int
+	// Concurrency is the maximum number of queries searched at once. Defaults to 4.
+	Concurrency int
+	// Limiter, if set, is waited on before each query or task, in addition to any limiters of the Searcher itself.
+	Limiter Limiter
+	// RateLimitRetries is the number of times a query refused with a RateLimitError is retried. Defaults to 3.
+	RateLimitRetries int
+	// RetryDelay is the delay before the first retry, doubled after each attempt. Defaults to 1 second.
+	RetryDelay time.Duration
+	// CheckpointPath, if set, is the file successful queries are recorded to as they complete.
+	// Running the same batch again with the same checkpoint resumes it, only searching queries not yet recorded.
+	// A checkpoint recorded with a different MaxResults or SearchOptions is rejected. Ignored by RunBatch.
+	CheckpointPath string
+}
+
+// A BatchResult holds the outcome of a single query of a batch.
+type BatchResult struct {
+	// Query is the search query.
+	Query string
+	// Results are the search results, if the query succeeded.
+	Results []SearchResult
+	// Err is the error the query failed with, if any.
+	Err error
+}
+
+// checkpointHeader is the first line of a checkpoint file, holding the options its results were searched with.
+type checkpointHeader struct {
+	MaxResults int           `json:"max_results"`
+	Options    SearchOptions `json:"options"`
+}
+
+// checkpointRecord is a line of a checkpoint file.
+type checkpointRecord struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
+
+// SearchBatch searches for many queries with bounded concurrency, for bulk jobs such as importing lists of titles.
+// A failing query doesn't stop the batch; its error is reported in its BatchResult.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - searcher: The Searcher to send queries to.
+//   - queries: The search queries.
+//   - opts: The options of the batch.
+//
+// Returns:
+//   - []BatchResult: The outcome of each query, in the order of queries.
+//   - error: An error if the checkpoint file cannot be read or written or was recorded with different options,
+//     or ctx.Err() if the batch was cancelled.
+func SearchBatch(ctx context.Context, searcher Searcher, queries []string, opts BatchOptions) ([]BatchResult, error) {
+	opts = opts.withDefaults()
+
+	completed := map[string][]SearchResult{}
+	var checkpoint *batchCheckpoint
+	if opts.CheckpointPath != "" {
+		var err error
+		header := checkpointHeader{MaxResults: opts.MaxResults, Options: SearchOptionsFromContext(ctx)}
+		if checkpoint, err = openBatchCheckpoint(opts.CheckpointPath, header, completed); err != nil {
+			return nil, err
+		}
+		defer checkpoint.close()
+	}
+
+	batch := make([]BatchResult, len(queries))
+	indexes := make([]int, 0, len(queries))
+	for i, query := range queries {
+		batch[i].Query = query
+
+		if results, ok := completed[query]; ok {
+			batch[i].Results = results
+			continue
+		}
+
+		indexes = append(indexes, i)
+	}
+
+	runWorkers(ctx, indexes, opts.Concurrency, func(i int) {
+		batch[i].Err = withRateLimitRetries(ctx, opts, func() error {
+			var err error
+			batch[i].Results, err = searcher.Search(ctx, queries[i], opts.MaxResults)
+			return err
+		})
+
+		if batch[i].Err == nil && checkpoint != nil {
+			checkpoint.record(queries[i], batch[i].Results)
+		}
+	}, func(i int) {
+		batch[i].Err = ctx.Err()
+	})
+
+	if checkpoint != nil {
+		if err := checkpoint.err(); err != nil {
+			return batch, err
+		}
+	}
+
+	return batch, ctx.Err()
+}
+
+// RunBatch runs a task for each of n items with the concurrency, limiter, and rate limit retries of SearchBatch,
+// for bulk jobs that are more than plain searches, such as matching imported lists of titles.
+// A failing task doesn't stop the batch; its error is reported at its index.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - n: The number of items.
+//   - opts: The options of the batch. MaxResults and CheckpointPath are ignored.
+//   - task: The task run for the item at index i. Tasks run concurrently, and are retried if they fail with a RateLimitError.
+//
+// Returns:
+//   - []error: The error each task failed with, if any, in the order of items.
+//   - error: ctx.Err() if the batch was cancelled.
+func RunBatch(ctx context.Context, n int, opts BatchOptions, task func(ctx context.Context, i int) error) ([]error, error) {
+	opts = opts.withDefaults()
+
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	errs := make([]error, n)
+	runWorkers(ctx, indexes, opts.Concurrency, func(i int) {
+		errs[i] = withRateLimitRetries(ctx, opts, func() error {
+			return task(ctx, i)
+		})
+	}, func(i int) {
+		errs[i] = ctx.Err()
+	})
+
+	return errs, ctx.Err()
+}
+
+// withDefaults returns the options with defaults applied to unset fields.
+func (opts BatchOptions) withDefaults() BatchOptions {
+	if opts.MaxResults <= 0 {
+		opts.MaxResults = 10
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+
+	if opts.RateLimitRetries <= 0 {
+		opts.RateLimitRetries = 3
+	}
+
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = time.Second
+	}
+
+	return opts
+}
+
+// runWorkers runs work for each index on up to concurrency goroutines, and returns once all of them are done.
+// Indexes not started because ctx was cancelled are passed to cancelled instead.
+func runWorkers(ctx context.Context, indexes []int, concurrency int, work func(i int), cancelled func(i int)) {
+	pending := make(chan int)
+	var wg sync.WaitGroup
+
+	for range min(concurrency, len(indexes)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range pending {
+				work(i)
+			}
+		}()
+	}
+
+	for _, i := range indexes {
+		if ctx.Err() != nil {
+			cancelled(i)
+			continue
+		}
+
+		select {
+		case pending <- i:
+		case <-ctx.Done():
+			cancelled(i)
+		}
+	}
+
+	close(pending)
+	wg.Wait()
+}
+
+// withRateLimitRetries runs a single query or task of a batch, retrying with exponential backoff while rate limited.
+func withRateLimitRetries(ctx context.Context, opts BatchOptions, run func() error) error {
+	delay := opts.RetryDelay
+
+	for attempt := 0; ; attempt++ {
+		if opts.Limiter != nil {
+			if err := opts.Limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		err := run()
+
+		// An exhausted quota doesn't reset until the next day, so retrying it only wastes the attempts
+		var rateLimitErr *RateLimitError
+		if !errors.As(err, &rateLimitErr) || rateLimitErr.QuotaExhausted() || attempt == opts.RateLimitRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// A batchCheckpoint records the successful queries of a batch to a file, one JSON record per line.
+type batchCheckpoint struct {
+	mu       sync.Mutex
+	file     *os.File
+	writeErr error
+}
+
+// openBatchCheckpoint opens or creates a checkpoint file, loading the queries it records into completed.
+// A new checkpoint starts with header; an existing one is rejected unless it starts with the same header.
+func openBatchCheckpoint(path string, header checkpointHeader, completed map[string][]SearchResult) (*batchCheckpoint, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<24)
+
+	if scanner.Scan() {
+		var recorded checkpointHeader
+		if err := json.Unmarshal(scanner.Bytes(), &recorded); err != nil || recorded != header {
+			file.Close()
+			return nil, fmt.Errorf("checkpoint %s was recorded with different options", path)
+		}
+	} else if scanner.Err() == nil {
+		line, err := json.Marshal(header)
+		if err == nil {
+			_, err = file.Write(append(line, '\n'))
+		}
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to write checkpoint: %w", err)
+		}
+	}
+
+	for scanner.Scan() {
+		var record checkpointRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// A torn line from an interrupted run; the query is simply searched again
+			continue
+		}
+
+		completed[record.Query] = record.Results
+	}
+
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	// Terminate a torn final line from an interrupted run, so the next record starts on its own line
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			if _, err := file.Write([]byte{'\n'}); err != nil {
+				file.Close()
+				return nil, fmt.Errorf("failed to write checkpoint: %w", err)
+			}
+		}
+	}
+
+	return &batchCheckpoint{file: file}, nil
+}
+
+// record appends a successful query to the checkpoint file.
+func (bc *batchCheckpoint) record(query string, results []SearchResult) {
+	line, err := json.Marshal(checkpointRecord{Query: query, Results: results})
+	if err != nil {
+		return
+	}
+
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if bc.writeErr != nil {
+		return
+	}
+
+	if _, err := bc.file.Write(append(line, '\n')); err != nil {
+		bc.writeErr = fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+}
+
+// err returns the first error encountered writing the checkpoint file.
+func (bc *batchCheckpoint) err() error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	return bc.writeErr
+}
+
+// close closes the checkpoint file.
+func (bc *batchCheckpoint) close() {
+	bc.file.Close()
+}
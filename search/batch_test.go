@@ -0,0 +1,265 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// batchSearcher returns a result titled after the query, failing queries listed in failures,
+// and tracks the peak number of concurrent searches.
+type batchSearcher struct {
+	failures map[string]error
+
+	mu      sync.Mutex
+	queries []string
+	active  int
+	peak    int
+}
+
+func (s *batchSearcher) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	s.mu.Lock()
+	s.queries = append(s.queries, query)
+	s.active++
+	s.peak = max(s.peak, s.active)
+	err := s.failures[query]
+	s.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	s.mu.Lock()
+	s.active--
+	s.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return []search.SearchResult{{Title: query}}, nil
+}
+
+// flakySearcher is rate limited for the first limited calls.
+type flakySearcher struct {
+	limited int32
+	calls   atomic.Int32
+}
+
+func (s *flakySearcher) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	if s.calls.Add(1) <= s.limited {
+		return nil, search.NewRateLimitError("too many requests")
+	}
+	return []search.SearchResult{{Title: query}}, nil
+}
+
+func TestSearchBatch(t *testing.T) {
+	providerErr := search.NewSearchProviderError("boom")
+	searcher := &batchSearcher{failures: map[string]error{"Heat": providerErr}}
+	queries := []string{"The Matrix", "Heat", "Alien", "Fargo", "Up", "Jaws"}
+
+	batch, err := search.SearchBatch(context.Background(), searcher, queries, search.BatchOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(batch) != len(queries) {
+		t.Fatalf("expected %d results, got %d", len(queries), len(batch))
+	}
+	for i, result := range batch {
+		if result.Query != queries[i] {
+			t.Errorf("expected results in query order, got %q at %d", result.Query, i)
+		}
+		if result.Query == "Heat" {
+			if result.Err != providerErr {
+				t.Errorf("expected the provider error for Heat, got %v", result.Err)
+			}
+		} else if result.Err != nil || len(result.Results) != 1 || result.Results[0].Title != result.Query {
+			t.Errorf("expected results for %q, got %+v", result.Query, result)
+		}
+	}
+
+	if searcher.peak > 2 {
+		t.Errorf("expected at most 2 concurrent searches, got %d", searcher.peak)
+	}
+}
+
+func TestSearchBatch_RetriesRateLimits(t *testing.T) {
+	searcher := &flakySearcher{limited: 2}
+
+	batch, err := search.SearchBatch(context.Background(), searcher, []string{"Heat"}, search.BatchOptions{RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if batch[0].Err != nil || searcher.calls.Load() != 3 {
+		t.Errorf("expected success after 2 retries, got %v after %d calls", batch[0].Err, searcher.calls.Load())
+	}
+}
+
+func TestSearchBatch_GivesUpOnRateLimits(t *testing.T) {
+	searcher := &flakySearcher{limited: 10}
+
+	batch, err := search.SearchBatch(context.Background(), searcher, []string{"Heat"},
+		search.BatchOptions{RateLimitRetries: 1, RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var rateLimitErr *search.RateLimitError
+	if !errors.As(batch[0].Err, &rateLimitErr) || searcher.calls.Load() != 2 {
+		t.Errorf("expected RateLimitError after 1 retry, got %v after %d calls", batch[0].Err, searcher.calls.Load())
+	}
+}
+
+func TestSearchBatch_DoesNotRetryExhaustedQuota(t *testing.T) {
+	searcher := &failingSearcher{err: search.NewQuotaExhaustedError("daily quota of 1000 requests exhausted")}
+
+	batch, err := search.SearchBatch(context.Background(), searcher, []string{"Heat"}, search.BatchOptions{RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var rateLimitErr *search.RateLimitError
+	if !errors.As(batch[0].Err, &rateLimitErr) || searcher.calls != 1 {
+		t.Errorf("expected the exhausted quota without retries, got %v after %d calls", batch[0].Err, searcher.calls)
+	}
+}
+
+func TestSearchBatch_Limiter(t *testing.T) {
+	limiter := search.NewRateLimiter(search.RateLimiterConfig{RequestsPerSecond: 100, Burst: 1})
+	queries := []string{"a", "b", "c", "d", "e", "f"}
+
+	start := time.Now()
+	if _, err := search.SearchBatch(context.Background(), &batchSearcher{}, queries, search.BatchOptions{Limiter: limiter}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("expected the limiter to pace queries, took %v", elapsed)
+	}
+}
+
+func TestSearchBatch_ResumesFromCheckpoint(t *testing.T) {
+	checkpoint := filepath.Join(t.TempDir(), "batch.checkpoint")
+	queries := []string{"The Matrix", "Heat", "Alien"}
+
+	first := &batchSearcher{failures: map[string]error{"Heat": search.NewSearchProviderError("boom")}}
+	if _, err := search.SearchBatch(context.Background(), first, queries, search.BatchOptions{CheckpointPath: checkpoint}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Simulate a run interrupted mid-write
+	file, _ := os.OpenFile(checkpoint, os.O_APPEND|os.O_WRONLY, 0)
+	_, _ = file.WriteString(`{"query":"Fa`)
+	file.Close()
+
+	second := &batchSearcher{}
+	batch, err := search.SearchBatch(context.Background(), second, queries, search.BatchOptions{CheckpointPath: checkpoint})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(second.queries) != 1 || second.queries[0] != "Heat" {
+		t.Errorf("expected only the failed query to be searched again, got %v", second.queries)
+	}
+	for _, result := range batch {
+		if result.Err != nil || len(result.Results) != 1 || result.Results[0].Title != result.Query {
+			t.Errorf("expected results for %q, got %+v", result.Query, result)
+		}
+	}
+
+	third := &batchSearcher{}
+	if _, err := search.SearchBatch(context.Background(), third, queries, search.BatchOptions{CheckpointPath: checkpoint}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(third.queries) != 0 {
+		t.Errorf("expected a completed batch not to search again, got %v", third.queries)
+	}
+}
+
+func TestSearchBatch_RejectsMismatchingCheckpoint(t *testing.T) {
+	queries := []string{"The Matrix", "Heat"}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		opts search.BatchOptions
+	}{
+		{"max results", context.Background(), search.BatchOptions{MaxResults: 5}},
+		{"search options", search.WithSearchOptions(context.Background(), search.SearchOptions{Language: "de"}), search.BatchOptions{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkpoint := filepath.Join(t.TempDir(), "batch.checkpoint")
+			if _, err := search.SearchBatch(context.Background(), &batchSearcher{}, queries, search.BatchOptions{CheckpointPath: checkpoint}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			tt.opts.CheckpointPath = checkpoint
+			searcher := &batchSearcher{}
+			if _, err := search.SearchBatch(tt.ctx, searcher, queries, tt.opts); err == nil {
+				t.Error("expected a checkpoint recorded with other options to be rejected")
+			}
+			if len(searcher.queries) != 0 {
+				t.Errorf("expected no queries to be searched, got %v", searcher.queries)
+			}
+		})
+	}
+}
+
+func TestSearchBatch_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	batch, err := search.SearchBatch(ctx, &batchSearcher{}, []string{"a", "b"}, search.BatchOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	for _, result := range batch {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("expected %q to be cancelled, got %+v", result.Query, result)
+		}
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	var calls atomic.Int32
+	var active, peak atomic.Int32
+	tasks := []error{nil, search.NewRateLimitError("too many requests"), errors.New("failed"), nil}
+
+	errs, err := search.RunBatch(context.Background(), len(tasks), search.BatchOptions{Concurrency: 2, RetryDelay: time.Millisecond},
+		func(ctx context.Context, i int) error {
+			calls.Add(1)
+			if n := active.Add(1); n > peak.Load() {
+				peak.Store(n)
+			}
+			defer active.Add(-1)
+
+			time.Sleep(5 * time.Millisecond)
+
+			// The rate limited task succeeds when retried
+			taskErr := tasks[i]
+			tasks[i] = nil
+			return taskErr
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if errs[0] != nil || errs[1] != nil || errs[2] == nil || errs[3] != nil {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if calls.Load() != 5 {
+		t.Errorf("expected the rate limited task to be retried once, got %d calls", calls.Load())
+	}
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent tasks, got %d", peak.Load())
+	}
+}
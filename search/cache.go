@@ -0,0 +1,206 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CachingSearcherConfig holds the configuration of a CachingSearcher.
+type CachingSearcherConfig struct {
+	// Store is where results are cached. Defaults to a MemoryCacheStore holding Capacity entries.
+	Store CacheStore
+	// Capacity is the capacity of the default MemoryCacheStore. Defaults to 1000.
+	Capacity int
+	// TTL is how long non-empty results are served from the cache as fresh. Defaults to 5 minutes.
+	TTL time.Duration
+	// StaleTTL is how long results are still served after TTL has elapsed, while they are refreshed in the background.
+	// Zero disables stale-while-revalidate.
+	StaleTTL time.Duration
+	// NegativeTTL is how long empty results are served from the cache. Defaults to 1 minute, short enough that
+	// newly listed titles show up quickly. Set it to a negative value to disable caching of empty results.
+	NegativeTTL time.Duration
+	// RefreshTimeout bounds background refreshes of stale results. Defaults to 10 seconds.
+	RefreshTimeout time.Duration
+	// TracerProvider traces searches, recording whether they hit the cache. Defaults to the global TracerProvider.
+	TracerProvider trace.TracerProvider
+	// Metrics records whether searches hit the cache. Defaults to recording nothing.
+	Metrics Metrics
+	// Name is the name lookups are recorded under, so that several caches can share a Metrics. Defaults to "cache".
+	Name string
+}
+
+// A CachingSearcher is a Searcher decorator that caches search results in a CacheStore.
+//
+// Results are served from the cache while fresh. Once stale, they are still served immediately while a background
+// request refreshes them, so popular searches never wait on the provider. Empty results are cached for NegativeTTL,
+// so junk queries don't keep hitting the provider. Errors are never cached.
+//
+// A failing CacheStore is treated as a cache miss, so searches fall back to the provider.
+type CachingSearcher struct {
+	searcher Searcher
+	config   CachingSearcherConfig
+	tracer   trace.Tracer
+
+	mu         sync.Mutex
+	refreshing map[string]bool
+}
+
+// NewCachingSearcher creates a new CachingSearcher that wraps the specified Searcher.
+//
+// Parameters:
+//   - searcher: The Searcher to send cache misses to.
+//   - config: The configuration of the CachingSearcher.
+//
+// Returns:
+//   - *CachingSearcher: A new instance of CachingSearcher.
+func NewCachingSearcher(searcher Searcher, config CachingSearcherConfig) *CachingSearcher {
+	if config.Capacity <= 0 {
+		config.Capacity = 1000
+	}
+
+	if config.TTL <= 0 {
+		config.TTL = 5 * time.Minute
+	}
+
+	if config.NegativeTTL == 0 {
+		config.NegativeTTL = time.Minute
+	}
+
+	if config.RefreshTimeout <= 0 {
+		config.RefreshTimeout = 10 * time.Second
+	}
+
+	if config.Store == nil {
+		config.Store = NewMemoryCacheStore(config.Capacity)
+	}
+
+	config.Metrics = metricsOrDefault(config.Metrics)
+
+	if config.Name == "" {
+		config.Name = "cache"
+	}
+
+	return &CachingSearcher{
+		searcher:   searcher,
+		config:     config,
+		tracer:     tracerOrDefault(config.TracerProvider),
+		refreshing: make(map[string]bool),
+	}
+}
+
+// Search returns cached results for the search if there are any, and otherwise performs the search and caches them.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - query: The search query string.
+//   - maxResults: The maximum number of search results to return.
+//
+// Returns:
+//   - []SearchResult: A slice containing the search results, owned by the caller.
+//   - error: An error if the search operation fails.
+func (cs *CachingSearcher) Search(ctx context.Context, query string, maxResults int) (results []SearchResult, err error) {
+	ctx, span := cs.tracer.Start(ctx, "CachingSearcher.Search", trace.WithAttributes(maxResultsAttribute.Int(maxResults)))
+	defer func() { endSpan(span, len(results), err) }()
+
+	if maxResults <= 0 {
+		return nil, NewInvalidMaxResultsError()
+	}
+
+	key := newSearchKey(ctx, query, maxResults).String()
+	now := time.Now()
+
+	entry, err := cs.config.Store.Get(ctx, key)
+	if err != nil {
+		log.Printf("Failed to read cached results for query \"%s\": %v\n", query, err)
+	}
+
+	if entry != nil && now.Before(entry.ExpiresAt) {
+		if !now.Before(entry.FreshUntil) {
+			cs.refresh(ctx, key, query, maxResults)
+		}
+
+		span.SetAttributes(cacheHitAttribute.Bool(true))
+		cs.config.Metrics.ObserveCache(cs.config.Name, true)
+
+		return slices.Clone(entry.Results), nil
+	}
+
+	span.SetAttributes(cacheHitAttribute.Bool(false))
+	cs.config.Metrics.ObserveCache(cs.config.Name, false)
+
+	results, err = cs.searcher.Search(ctx, query, maxResults)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.put(ctx, key, results)
+
+	return slices.Clone(results), nil
+}
+
+// refresh performs the search in the background and caches its results, unless a refresh of the key is in progress.
+// The refresh keeps the values of ctx (such as SearchOptions) but not its cancellation.
+func (cs *CachingSearcher) refresh(ctx context.Context, key string, query string, maxResults int) {
+	cs.mu.Lock()
+	if cs.refreshing[key] {
+		cs.mu.Unlock()
+		return
+	}
+
+	cs.refreshing[key] = true
+	cs.mu.Unlock()
+
+	go func() {
+		defer func() {
+			cs.mu.Lock()
+			delete(cs.refreshing, key)
+			cs.mu.Unlock()
+		}()
+
+		refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cs.config.RefreshTimeout)
+		defer cancel()
+
+		results, err := cs.searcher.Search(refreshCtx, query, maxResults)
+		if err != nil {
+			// Keep serving the stale results until they expire
+			log.Printf("Failed to refresh cached results for query \"%s\": %v\n", query, err)
+			return
+		}
+
+		cs.put(refreshCtx, key, results)
+	}()
+}
+
+// put caches the results for the key, with a lifetime depending on whether they are empty.
+func (cs *CachingSearcher) put(ctx context.Context, key string, results []SearchResult) {
+	ttl, staleTTL := cs.config.TTL, cs.config.StaleTTL
+	if len(results) == 0 {
+		ttl, staleTTL = cs.config.NegativeTTL, 0
+	}
+
+	if ttl <= 0 {
+		return
+	}
+
+	now := time.Now()
+	entry := &CacheEntry{
+		Results:    slices.Clone(results),
+		FreshUntil: now.Add(ttl),
+		ExpiresAt:  now.Add(ttl + staleTTL),
+	}
+
+	if err := cs.config.Store.Set(ctx, key, entry); err != nil {
+		log.Printf("Failed to cache results for key \"%s\": %v\n", key, err)
+	}
+}
+
+// String encodes the key as a string suitable for use as a cache key.
+func (k searchKey) String() string {
+	return fmt.Sprintf("%d|%+v|%s", k.maxResults, k.options, k.query)
+}
@@ -0,0 +1,178 @@
+package search
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// cacheSchemaVersion is the version of the serialized CacheEntry format.
+// It must be incremented whenever CacheEntry or SearchResult change shape, so stale entries are discarded.
+const cacheSchemaVersion = 2
+
+// A CacheEntry is a cached search.
+type CacheEntry struct {
+	// Results are the cached search results.
+	Results []SearchResult
+	// FreshUntil is when the results become stale and are refreshed.
+	FreshUntil time.Time
+	// ExpiresAt is when the results may no longer be served. Stores may discard the entry from then on.
+	ExpiresAt time.Time
+}
+
+// A CacheStore is where a CachingSearcher keeps cached searches.
+// Implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Get returns the entry cached under key, or nil if there is none.
+	//
+	// Parameters:
+	//   - ctx: The context for controlling cancellation and deadlines.
+	//   - key: The cache key.
+	//
+	// Returns:
+	//   - *CacheEntry: The cached entry, or nil on a cache miss.
+	//   - error: An error if the store could not be read.
+	Get(ctx context.Context, key string) (*CacheEntry, error)
+
+	// Set caches the entry under key until it expires.
+	//
+	// Parameters:
+	//   - ctx: The context for controlling cancellation and deadlines.
+	//   - key: The cache key.
+	//   - entry: The entry to cache.
+	//
+	// Returns:
+	//   - error: An error if the store could not be written.
+	Set(ctx context.Context, key string, entry *CacheEntry) error
+}
+
+// serializedCacheEntry is the versioned wire format of a CacheEntry.
+type serializedCacheEntry struct {
+	Version    int            `json:"version"`
+	Results    []SearchResult `json:"results"`
+	FreshUntil time.Time      `json:"fresh_until"`
+	ExpiresAt  time.Time      `json:"expires_at"`
+}
+
+// MarshalCacheEntry serializes a CacheEntry, tagged with the current schema version, for stores that hold bytes.
+//
+// Parameters:
+//   - entry: The entry to serialize.
+//
+// Returns:
+//   - []byte: The serialized entry.
+//   - error: An error if the entry could not be serialized.
+func MarshalCacheEntry(entry *CacheEntry) ([]byte, error) {
+	return json.Marshal(serializedCacheEntry{
+		Version:    cacheSchemaVersion,
+		Results:    entry.Results,
+		FreshUntil: entry.FreshUntil,
+		ExpiresAt:  entry.ExpiresAt,
+	})
+}
+
+// UnmarshalCacheEntry deserializes a CacheEntry serialized by MarshalCacheEntry.
+// Entries serialized with a different schema version are discarded.
+//
+// Parameters:
+//   - data: The serialized entry.
+//
+// Returns:
+//   - *CacheEntry: The deserialized entry, or nil if it was serialized with a different schema version.
+//   - error: A ResultParsingError if the data is corrupt.
+func UnmarshalCacheEntry(data []byte) (*CacheEntry, error) {
+	var serialized serializedCacheEntry
+	if err := json.Unmarshal(data, &serialized); err != nil {
+		return nil, NewResultParsingError(err.Error())
+	}
+
+	if serialized.Version != cacheSchemaVersion {
+		return nil, nil
+	}
+
+	return &CacheEntry{
+		Results:    serialized.Results,
+		FreshUntil: serialized.FreshUntil,
+		ExpiresAt:  serialized.ExpiresAt,
+	}, nil
+}
+
+// memoryCacheItem is an entry held by a MemoryCacheStore.
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// A MemoryCacheStore is an in-memory CacheStore that evicts the least recently used entries once full.
+type MemoryCacheStore struct {
+	capacity int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// NewMemoryCacheStore creates a new, empty MemoryCacheStore.
+//
+// Parameters:
+//   - capacity: The maximum number of entries held. Defaults to 1000 if not positive.
+//
+// Returns:
+//   - *MemoryCacheStore: A new instance of MemoryCacheStore.
+func NewMemoryCacheStore(capacity int) *MemoryCacheStore {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+
+	return &MemoryCacheStore{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Get returns the entry cached under key, marking it as recently used.
+func (ms *MemoryCacheStore) Get(ctx context.Context, key string) (*CacheEntry, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	element, ok := ms.entries[key]
+	if !ok {
+		return nil, nil
+	}
+
+	item := element.Value.(*memoryCacheItem)
+	if !time.Now().Before(item.entry.ExpiresAt) {
+		ms.lru.Remove(element)
+		delete(ms.entries, key)
+		return nil, nil
+	}
+
+	ms.lru.MoveToFront(element)
+
+	return item.entry, nil
+}
+
+// Set caches the entry under key, evicting the least recently used entry if the store is full.
+func (ms *MemoryCacheStore) Set(ctx context.Context, key string, entry *CacheEntry) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if element, ok := ms.entries[key]; ok {
+		element.Value.(*memoryCacheItem).entry = entry
+		ms.lru.MoveToFront(element)
+		return nil
+	}
+
+	ms.entries[key] = ms.lru.PushFront(&memoryCacheItem{key: key, entry: entry})
+
+	for ms.lru.Len() > ms.capacity {
+		oldest := ms.lru.Back()
+		ms.lru.Remove(oldest)
+		delete(ms.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+
+	return nil
+}
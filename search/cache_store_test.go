@@ -0,0 +1,213 @@
+package search_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+func newTestCacheEntry(title string, ttl time.Duration) *search.CacheEntry {
+	return &search.CacheEntry{
+		Results:    []search.SearchResult{{Title: title, Type: search.Movie}},
+		FreshUntil: time.Now().Add(ttl),
+		ExpiresAt:  time.Now().Add(ttl),
+	}
+}
+
+func TestMemoryCacheStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	store := search.NewMemoryCacheStore(2)
+
+	_ = store.Set(ctx, "a", newTestCacheEntry("a", time.Minute))
+	_ = store.Set(ctx, "b", newTestCacheEntry("b", time.Minute))
+	_, _ = store.Get(ctx, "a")
+	_ = store.Set(ctx, "c", newTestCacheEntry("c", time.Minute))
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		entry, err := store.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if (entry != nil) != want {
+			t.Errorf("expected presence of %q to be %t", key, want)
+		}
+	}
+}
+
+func TestMemoryCacheStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	store := search.NewMemoryCacheStore(10)
+
+	_ = store.Set(ctx, "a", newTestCacheEntry("a", -time.Second))
+	if entry, _ := store.Get(ctx, "a"); entry != nil {
+		t.Errorf("expected expired entry to be a miss, got %+v", entry)
+	}
+}
+
+func TestDiskCacheStore_SurvivesReopen(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := search.NewDiskCacheStore(dir, search.DiskCacheStoreConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Set(ctx, "matrix", newTestCacheEntry("The Matrix", time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reopened, err := search.NewDiskCacheStore(dir, search.DiskCacheStoreConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entry, err := reopened.Get(ctx, "matrix")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry == nil || len(entry.Results) != 1 || entry.Results[0].Title != "The Matrix" || entry.Results[0].Type != search.Movie {
+		t.Errorf("expected cached entry after reopening, got %+v", entry)
+	}
+}
+
+func TestDiskCacheStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := search.NewDiskCacheStore(dir, search.DiskCacheStoreConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_ = store.Set(ctx, "matrix", newTestCacheEntry("The Matrix", -time.Second))
+	if entry, _ := store.Get(ctx, "matrix"); entry != nil {
+		t.Errorf("expected expired entry to be a miss, got %+v", entry)
+	}
+
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("expected expired entry to be deleted, found %d files", len(files))
+	}
+}
+
+func TestDiskCacheStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	store, err := search.NewDiskCacheStore(t.TempDir(), search.DiskCacheStoreConfig{MaxEntries: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_ = store.Set(ctx, "a", newTestCacheEntry("a", time.Minute))
+	time.Sleep(5 * time.Millisecond)
+	_ = store.Set(ctx, "b", newTestCacheEntry("b", time.Minute))
+	time.Sleep(5 * time.Millisecond)
+	_, _ = store.Get(ctx, "a")
+	time.Sleep(5 * time.Millisecond)
+	_ = store.Set(ctx, "c", newTestCacheEntry("c", time.Minute))
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		entry, err := store.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if (entry != nil) != want {
+			t.Errorf("expected presence of %q to be %t", key, want)
+		}
+	}
+}
+
+func TestDiskCacheStore_MaxBytes(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := search.NewDiskCacheStore(dir, search.DiskCacheStoreConfig{MaxBytes: 512})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		_ = store.Set(ctx, fmt.Sprint(i), newTestCacheEntry(fmt.Sprint(i), time.Minute))
+	}
+
+	var total int64
+	files, _ := os.ReadDir(dir)
+	for _, file := range files {
+		info, _ := file.Info()
+		total += info.Size()
+	}
+	if total > 512 || len(files) == 0 {
+		t.Errorf("expected between 1 and 512 bytes on disk, got %d bytes in %d files", total, len(files))
+	}
+}
+
+func TestDiskCacheStore_SchemaVersionMismatch(t *testing.T) {
+	dir := t.TempDir()
+	store, err := search.NewDiskCacheStore(dir, search.DiskCacheStoreConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte("matrix"))
+	path := filepath.Join(dir, hex.EncodeToString(sum[:])+".cache")
+	old := `{"version":0,"results":[{"Title":"The Matrix"}],"fresh_until":"2999-01-01T00:00:00Z","expires_at":"2999-01-01T00:00:00Z"}`
+	if err := os.WriteFile(path, []byte(old), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entry, err := store.Get(context.Background(), "matrix")
+	if err != nil || entry != nil {
+		t.Errorf("expected entry from an old schema to be a miss, got %+v, %v", entry, err)
+	}
+}
+
+func TestDiskCacheStore_ConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	store, err := search.NewDiskCacheStore(t.TempDir(), search.DiskCacheStoreConfig{MaxEntries: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprint(i % 7)
+			if err := store.Set(ctx, key, newTestCacheEntry(key, time.Minute)); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if _, err := store.Get(ctx, key); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCachingSearcher_Search_DiskCacheStore(t *testing.T) {
+	dir := t.TempDir()
+	searcher := &countingSearcher{}
+
+	for i := 0; i < 2; i++ {
+		// Reopen the store each time, as a restarted process would
+		store, err := search.NewDiskCacheStore(dir, search.DiskCacheStoreConfig{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cache := search.NewCachingSearcher(searcher, search.CachingSearcherConfig{Store: store, TTL: time.Minute})
+		if _, err := cache.Search(context.Background(), "Matrix", 5); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if calls := searcher.calls.Load(); calls != 1 {
+		t.Errorf("expected cache to survive a restart, got %d upstream calls", calls)
+	}
+}
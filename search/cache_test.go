@@ -0,0 +1,181 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// countingSearcher returns a result titled after the query and the call number, or no results for "junk".
+type countingSearcher struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (s *countingSearcher) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	call := s.calls.Add(1)
+	if s.err != nil {
+		return nil, s.err
+	}
+	if query == "junk" {
+		return []search.SearchResult{}, nil
+	}
+	return []search.SearchResult{{Title: query, Year: string('0' + rune(call))}}, nil
+}
+
+func TestCachingSearcher_Search_Hit(t *testing.T) {
+	searcher := &countingSearcher{}
+	cache := search.NewCachingSearcher(searcher, search.CachingSearcherConfig{TTL: time.Minute})
+
+	for _, query := range []string{"Star Wars", "star wars", "  STAR  WARS "} {
+		results, err := cache.Search(context.Background(), query, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 1 || results[0].Year != "1" {
+			t.Errorf("expected cached results, got %+v", results)
+		}
+	}
+
+	if calls := searcher.calls.Load(); calls != 1 {
+		t.Errorf("expected 1 upstream call, got %d", calls)
+	}
+}
+
+func TestCachingSearcher_Search_EvictsLeastRecentlyUsed(t *testing.T) {
+	searcher := &countingSearcher{}
+	cache := search.NewCachingSearcher(searcher, search.CachingSearcherConfig{Capacity: 2, TTL: time.Minute})
+
+	for _, query := range []string{"a", "b", "a", "c", "a", "b"} {
+		if _, err := cache.Search(context.Background(), query, 5); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// "a" stays cached as the most recently used, while "b" is evicted by "c" and fetched again
+	if calls := searcher.calls.Load(); calls != 4 {
+		t.Errorf("expected 4 upstream calls, got %d", calls)
+	}
+}
+
+func TestCachingSearcher_Search_StaleWhileRevalidate(t *testing.T) {
+	searcher := &countingSearcher{}
+	cache := search.NewCachingSearcher(searcher, search.CachingSearcherConfig{
+		TTL:      10 * time.Millisecond,
+		StaleTTL: time.Minute,
+	})
+
+	if _, err := cache.Search(context.Background(), "Matrix", 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	results, err := cache.Search(context.Background(), "Matrix", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Year != "1" {
+		t.Errorf("expected stale results to be served, got %+v", results)
+	}
+
+	// Wait for the background refresh to land
+	deadline := time.Now().Add(time.Second)
+	for {
+		results, err = cache.Search(context.Background(), "Matrix", 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if results[0].Year == "2" || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if results[0].Year != "2" {
+		t.Errorf("expected refreshed results, got %+v", results)
+	}
+}
+
+func TestCachingSearcher_Search_Expired(t *testing.T) {
+	searcher := &countingSearcher{}
+	cache := search.NewCachingSearcher(searcher, search.CachingSearcherConfig{TTL: 10 * time.Millisecond})
+
+	_, _ = cache.Search(context.Background(), "Matrix", 5)
+	time.Sleep(20 * time.Millisecond)
+
+	results, err := cache.Search(context.Background(), "Matrix", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Year != "2" || searcher.calls.Load() != 2 {
+		t.Errorf("expected expired results to be fetched synchronously, got %+v", results)
+	}
+}
+
+func TestCachingSearcher_Search_NegativeCaching(t *testing.T) {
+	searcher := &countingSearcher{}
+	cache := search.NewCachingSearcher(searcher, search.CachingSearcherConfig{
+		TTL:         time.Minute,
+		NegativeTTL: 10 * time.Millisecond,
+	})
+
+	for i := 0; i < 3; i++ {
+		results, err := cache.Search(context.Background(), "junk", 5)
+		if err != nil || len(results) != 0 {
+			t.Fatalf("expected empty results, got %+v, %v", results, err)
+		}
+	}
+	if calls := searcher.calls.Load(); calls != 1 {
+		t.Errorf("expected empty results to be cached, got %d calls", calls)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	_, _ = cache.Search(context.Background(), "junk", 5)
+	if calls := searcher.calls.Load(); calls != 2 {
+		t.Errorf("expected empty results to expire after the negative TTL, got %d calls", calls)
+	}
+}
+
+func TestCachingSearcher_Search_NegativeCachingDefault(t *testing.T) {
+	searcher := &countingSearcher{}
+	cache := search.NewCachingSearcher(searcher, search.CachingSearcherConfig{})
+
+	for i := 0; i < 3; i++ {
+		_, _ = cache.Search(context.Background(), "junk", 5)
+	}
+	if calls := searcher.calls.Load(); calls != 1 {
+		t.Errorf("expected empty results to be cached by default, got %d calls", calls)
+	}
+}
+
+func TestCachingSearcher_Search_NegativeCachingDisabled(t *testing.T) {
+	searcher := &countingSearcher{}
+	cache := search.NewCachingSearcher(searcher, search.CachingSearcherConfig{NegativeTTL: -1})
+
+	for i := 0; i < 3; i++ {
+		_, _ = cache.Search(context.Background(), "junk", 5)
+	}
+	if calls := searcher.calls.Load(); calls != 3 {
+		t.Errorf("expected empty results not to be cached, got %d calls", calls)
+	}
+}
+
+func TestCachingSearcher_Search_ErrorsNotCached(t *testing.T) {
+	searcher := &countingSearcher{err: search.NewSearchProviderError("down")}
+	cache := search.NewCachingSearcher(searcher, search.CachingSearcherConfig{NegativeTTL: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		var spErr *search.SearchProviderError
+		if _, err := cache.Search(context.Background(), "Matrix", 5); !errors.As(err, &spErr) {
+			t.Fatalf("expected search provider error, got %v", err)
+		}
+	}
+	if calls := searcher.calls.Load(); calls != 2 {
+		t.Errorf("expected errors not to be cached, got %d calls", calls)
+	}
+}
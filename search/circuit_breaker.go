@@ -0,0 +1,235 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through to the wrapped Searcher.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every request without calling the wrapped Searcher.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of trial requests through to probe whether the provider has recovered.
+	CircuitHalfOpen
+)
+
+// String returns the name of the CircuitState.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig holds the configuration of a CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit. Defaults to 5.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before allowing trial requests. Defaults to 30 seconds.
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests is the number of concurrent trial requests allowed while half-open. Defaults to 1.
+	HalfOpenMaxRequests int
+	// SuccessThreshold is the number of successful trial requests that closes the circuit. Defaults to 1.
+	SuccessThreshold int
+	// IsFailure reports whether an error counts as a provider failure. By default, every error counts
+	// except InvalidMaxResultsError and context cancellation, which are the caller's doing, and
+	// RateLimitError, which means the provider is up but the request was throttled.
+	IsFailure func(err error) bool
+	// OnStateChange, if set, is called after every state transition.
+	OnStateChange func(from, to CircuitState)
+}
+
+// A CircuitBreaker is a Searcher decorator that stops calling a failing provider,
+// so callers fail immediately rather than waiting for the provider to time out.
+type CircuitBreaker struct {
+	searcher Searcher
+	config   CircuitBreakerConfig
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	successes int
+	inFlight  int
+	openedAt  time.Time
+}
+
+// NewCircuitBreaker creates a new, closed CircuitBreaker that wraps the specified Searcher.
+//
+// Parameters:
+//   - searcher: The Searcher to protect.
+//   - config: The configuration of the CircuitBreaker.
+//
+// Returns:
+//   - *CircuitBreaker: A new instance of CircuitBreaker.
+func NewCircuitBreaker(searcher Searcher, config CircuitBreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+
+	if config.HalfOpenMaxRequests <= 0 {
+		config.HalfOpenMaxRequests = 1
+	}
+
+	if config.SuccessThreshold <= 0 {
+		config.SuccessThreshold = 1
+	}
+
+	if config.IsFailure == nil {
+		config.IsFailure = isProviderFailure
+	}
+
+	return &CircuitBreaker{
+		searcher: searcher,
+		config:   config,
+	}
+}
+
+// Search performs a search using the wrapped Searcher, unless the circuit is open.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - query: The search query string.
+//   - maxResults: The maximum number of search results to return.
+//
+// Returns:
+//   - []SearchResult: A slice containing the search results.
+//   - error: A CircuitOpenError if the circuit is open, or an error if the search operation fails.
+func (cb *CircuitBreaker) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	if err := cb.acquire(); err != nil {
+		return nil, err
+	}
+
+	results, err := cb.searcher.Search(ctx, query, maxResults)
+	cb.release(err)
+
+	return results, err
+}
+
+// State returns the current state of the circuit.
+// An open circuit whose timeout has elapsed is reported, and transitioned, as half-open.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	from := cb.state
+	cb.expire()
+	to := cb.state
+	cb.mu.Unlock()
+
+	cb.notify(from, to)
+
+	return to
+}
+
+// acquire admits a request, or returns a CircuitOpenError if the circuit rejects it.
+func (cb *CircuitBreaker) acquire() error {
+	cb.mu.Lock()
+	from := cb.state
+	cb.expire()
+	to := cb.state
+
+	var err error
+	switch {
+	case cb.state == CircuitOpen:
+		err = NewCircuitOpenError()
+	case cb.state == CircuitHalfOpen && cb.inFlight >= cb.config.HalfOpenMaxRequests:
+		err = NewCircuitOpenError()
+	default:
+		cb.inFlight++
+	}
+
+	cb.mu.Unlock()
+
+	cb.notify(from, to)
+
+	return err
+}
+
+// release records the outcome of an admitted request and transitions the circuit accordingly.
+func (cb *CircuitBreaker) release(err error) {
+	cb.mu.Lock()
+	from := cb.state
+	cb.inFlight--
+
+	failed := err != nil && cb.config.IsFailure(err)
+
+	switch {
+	case failed && cb.state == CircuitOpen:
+		// A request admitted before the circuit opened failed late, which mustn't extend the open timeout
+	case failed && cb.state == CircuitHalfOpen:
+		cb.open()
+	case failed:
+		cb.failures++
+		if cb.failures >= cb.config.FailureThreshold {
+			cb.open()
+		}
+	case err != nil:
+		// Errors that aren't the provider's fault say nothing about its health
+	case cb.state == CircuitHalfOpen:
+		cb.successes++
+		if cb.successes >= cb.config.SuccessThreshold {
+			cb.state, cb.failures, cb.successes = CircuitClosed, 0, 0
+		}
+	default:
+		cb.failures = 0
+	}
+
+	to := cb.state
+	cb.mu.Unlock()
+
+	cb.notify(from, to)
+}
+
+// open transitions the circuit to the open state. The caller must hold cb.mu.
+func (cb *CircuitBreaker) open() {
+	cb.state, cb.failures, cb.successes, cb.openedAt = CircuitOpen, 0, 0, time.Now()
+}
+
+// expire transitions an open circuit whose timeout has elapsed to half-open. The caller must hold cb.mu.
+func (cb *CircuitBreaker) expire() {
+	if cb.state == CircuitOpen && time.Since(cb.openedAt) >= cb.config.OpenTimeout {
+		cb.state, cb.successes = CircuitHalfOpen, 0
+	}
+}
+
+// notify calls the OnStateChange callback if the state changed. The caller must not hold cb.mu.
+func (cb *CircuitBreaker) notify(from, to CircuitState) {
+	if from != to && cb.config.OnStateChange != nil {
+		cb.config.OnStateChange(from, to)
+	}
+}
+
+// isProviderFailure reports whether an error is attributable to the search provider. Rate limit errors
+// aren't, as a provider enforcing its limits, or a local limiter refusing a request, is still healthy.
+func isProviderFailure(err error) bool {
+	var mrErr *InvalidMaxResultsError
+	var rlErr *RateLimitError
+	return !errors.As(err, &mrErr) && !errors.As(err, &rlErr) && !errors.Is(err, context.Canceled)
+}
+
+// CircuitOpenError is an error type that is returned when a CircuitBreaker rejects a request.
+type CircuitOpenError struct{}
+
+// NewCircuitOpenError creates a new CircuitOpenError.
+func NewCircuitOpenError() *CircuitOpenError {
+	return &CircuitOpenError{}
+}
+
+// Error returns the default error message associated with the CircuitOpenError.
+func (e *CircuitOpenError) Error() string {
+	return "circuit breaker is open"
+}
@@ -0,0 +1,225 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// failingSearcher fails with err until it is healed, and counts its calls.
+type failingSearcher struct {
+	err   error
+	calls int
+}
+
+func (s *failingSearcher) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []search.SearchResult{{Title: query}}, nil
+}
+
+func TestCircuitBreaker_Search_Opens(t *testing.T) {
+	searcher := &failingSearcher{err: search.NewSearchProviderError("down")}
+
+	var transitions []search.CircuitState
+	breaker := search.NewCircuitBreaker(searcher, search.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Hour,
+		OnStateChange: func(from, to search.CircuitState) {
+			transitions = append(transitions, to)
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		var spErr *search.SearchProviderError
+		if _, err := breaker.Search(context.Background(), "Test", 5); !errors.As(err, &spErr) {
+			t.Fatalf("expected search provider error, got %v", err)
+		}
+	}
+
+	_, err := breaker.Search(context.Background(), "Test", 5)
+	var coErr *search.CircuitOpenError
+	if err == nil || !errors.As(err, &coErr) {
+		t.Fatalf("expected circuit open error, got %v", err)
+	}
+	if searcher.calls != 2 {
+		t.Errorf("expected open circuit not to call the searcher, got %d calls", searcher.calls)
+	}
+	if breaker.State() != search.CircuitOpen || len(transitions) != 1 || transitions[0] != search.CircuitOpen {
+		t.Errorf("expected a single transition to open, got %v", transitions)
+	}
+}
+
+func TestCircuitBreaker_Search_HalfOpenRecovers(t *testing.T) {
+	searcher := &failingSearcher{err: search.NewSearchProviderError("down")}
+
+	var transitions []search.CircuitState
+	breaker := search.NewCircuitBreaker(searcher, search.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Millisecond,
+		OnStateChange: func(from, to search.CircuitState) {
+			transitions = append(transitions, to)
+		},
+	})
+
+	if _, err := breaker.Search(context.Background(), "Test", 5); err == nil {
+		t.Fatal("expected error")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if state := breaker.State(); state != search.CircuitHalfOpen {
+		t.Fatalf("expected half-open circuit, got %v", state)
+	}
+
+	searcher.err = nil
+	if _, err := breaker.Search(context.Background(), "Test", 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []search.CircuitState{search.CircuitOpen, search.CircuitHalfOpen, search.CircuitClosed}
+	if len(transitions) != len(want) {
+		t.Fatalf("expected transitions %v, got %v", want, transitions)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("expected transitions %v, got %v", want, transitions)
+		}
+	}
+}
+
+func TestCircuitBreaker_Search_HalfOpenFailureReopens(t *testing.T) {
+	searcher := &failingSearcher{err: search.NewSearchProviderError("down")}
+	breaker := search.NewCircuitBreaker(searcher, search.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Millisecond,
+	})
+
+	_, _ = breaker.Search(context.Background(), "Test", 5)
+	time.Sleep(20 * time.Millisecond)
+	_, _ = breaker.Search(context.Background(), "Test", 5)
+
+	if state := breaker.State(); state != search.CircuitOpen {
+		t.Errorf("expected failed trial to reopen the circuit, got %v", state)
+	}
+}
+
+func TestCircuitBreaker_Search_IgnoresCallerErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		searcher search.Searcher
+	}{
+		{"cancellation", &failingSearcher{err: context.Canceled}},
+		// The request to the provider fails with the cancellation, wrapped in a SearchProviderError
+		{"cancelled request", search.NewTmdbSearcher(testAPIKey, http.DefaultClient)},
+		{"rate limit", &failingSearcher{err: search.NewRateLimitError("daily quota of 1000 requests exhausted")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := search.NewCircuitBreaker(tt.searcher, search.CircuitBreakerConfig{FailureThreshold: 1})
+
+			if _, err := breaker.Search(ctx, "Test", 5); err == nil {
+				t.Fatal("expected an error")
+			}
+			if state := breaker.State(); state != search.CircuitClosed {
+				t.Errorf("expected the error not to open the circuit, got %v", state)
+			}
+		})
+	}
+}
+
+// gatedSearcher fails every search, holding back searches for "slow" until the gate is closed.
+type gatedSearcher struct {
+	gate chan struct{}
+}
+
+func (s *gatedSearcher) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	if query == "slow" {
+		<-s.gate
+	}
+
+	return nil, search.NewSearchProviderError("down")
+}
+
+func TestCircuitBreaker_Search_LateFailureKeepsTimeout(t *testing.T) {
+	searcher := &gatedSearcher{gate: make(chan struct{})}
+	breaker := search.NewCircuitBreaker(searcher, search.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      50 * time.Millisecond,
+	})
+
+	// A slow request is admitted while the circuit is closed
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = breaker.Search(context.Background(), "slow", 5)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	_, _ = breaker.Search(context.Background(), "fast", 5)
+	if state := breaker.State(); state != search.CircuitOpen {
+		t.Fatalf("expected the circuit to open, got %v", state)
+	}
+
+	// The slow request fails once the circuit is already open
+	time.Sleep(30 * time.Millisecond)
+	close(searcher.gate)
+	<-done
+
+	time.Sleep(30 * time.Millisecond)
+	if state := breaker.State(); state != search.CircuitHalfOpen {
+		t.Errorf("expected the circuit to half-open after its original timeout, got %v", state)
+	}
+}
+
+func TestFallbackSearcher_Search_FallsBack(t *testing.T) {
+	primary := &failingSearcher{err: search.NewSearchProviderError("down")}
+	secondary := &failingSearcher{}
+
+	fallback := search.NewFallbackSearcher(primary, secondary)
+	results, err := fallback.Search(context.Background(), "Test", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || primary.calls != 1 || secondary.calls != 1 {
+		t.Errorf("expected secondary results after primary failure, got %+v", results)
+	}
+}
+
+func TestFallbackSearcher_Search_SkipsOpenCircuit(t *testing.T) {
+	primary := &failingSearcher{err: search.NewSearchProviderError("down")}
+	breaker := search.NewCircuitBreaker(primary, search.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour})
+	secondary := &failingSearcher{}
+
+	fallback := search.NewFallbackSearcher(breaker, secondary)
+	for i := 0; i < 3; i++ {
+		if _, err := fallback.Search(context.Background(), "Test", 5); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if primary.calls != 1 {
+		t.Errorf("expected open circuit to be skipped, got %d primary calls", primary.calls)
+	}
+}
+
+func TestFallbackSearcher_Search_AllFail(t *testing.T) {
+	fallback := search.NewFallbackSearcher(
+		&failingSearcher{err: search.NewSearchProviderError("first")},
+		&failingSearcher{err: search.NewSearchProviderError("second")},
+	)
+
+	_, err := fallback.Search(context.Background(), "Test", 5)
+	if err == nil || err.Error() != "second" {
+		t.Fatalf("expected last error, got %v", err)
+	}
+}
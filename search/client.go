@@ -2,6 +2,7 @@ package search
 
 import (
 	"context"
+	"fmt"
 )
 
 /* Restrict the possible values of the Type field to the following:
@@ -25,6 +26,34 @@ type SearchResult struct {
 	ProviderId string
 	PosterURL  string
 	Type       ResultType
+
+	// OriginalTitle is the title in its original language, if the provider reports it.
+	OriginalTitle string
+	// OriginalLanguage is the ISO 639-1 code of the title's original language, if the provider reports it.
+	OriginalLanguage string
+	// Adult reports whether the provider flags the title as adult content.
+	Adult bool
+	// TmdbID is the TMDB ID of the title, if known.
+	TmdbID string
+}
+
+// StartYear returns the year the title was first released. Years are usually a single year, but OMDB reports
+// the run of a series, such as "2008–2013" or "2008–".
+//
+// Returns:
+//   - string: The leading four-digit year of Year, or an empty string if Year doesn't start with one.
+func (r SearchResult) StartYear() string {
+	if len(r.Year) < 4 {
+		return ""
+	}
+
+	for _, c := range r.Year[:4] {
+		if c < '0' || c > '9' {
+			return ""
+		}
+	}
+
+	return r.Year[:4]
 }
 
 // A Searcher is a service that can search for movies, series, and episodes by title, and return zero or more matching results.
@@ -43,6 +72,53 @@ type Searcher interface {
 	Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error)
 }
 
+// A Rating represents a single score awarded to a title by a rating source (e.g. "Internet Movie Database").
+type Rating struct {
+	Source string
+	Value  string
+}
+
+// TitleDetails represents the full record of a single movie, series, or episode.
+type TitleDetails struct {
+	SearchResult
+	Plot      string
+	Rated     string
+	Runtime   int // Runtime in minutes, or zero if unknown.
+	Genres    []string
+	Cast      []string
+	Directors []string
+	Ratings   []Rating
+	Languages []string
+	Countries []string
+}
+
+// A Lookuper is a service that can retrieve the full details of a single title by its identifier.
+type Lookuper interface {
+	// LookupByImdbID retrieves the details of the title with the given IMDb ID (e.g. "tt0133093").
+	//
+	// Parameters:
+	//   - ctx: The context for controlling cancellation and deadlines.
+	//   - imdbID: The IMDb ID of the title.
+	//
+	// Returns:
+	//   - *TitleDetails: The details of the matching title.
+	//   - error: A TitleNotFoundError if no title matches, or another error if the lookup fails.
+	LookupByImdbID(ctx context.Context, imdbID string) (*TitleDetails, error)
+
+	// LookupByProviderID retrieves the details of the title with the given provider-specific ID,
+	// as returned in the ProviderId field of a SearchResult.
+	//
+	// Parameters:
+	//   - ctx: The context for controlling cancellation and deadlines.
+	//   - providerID: The provider-specific ID of the title.
+	//   - resultType: The type of the title, for providers that namespace IDs by type.
+	//
+	// Returns:
+	//   - *TitleDetails: The details of the matching title.
+	//   - error: A TitleNotFoundError if no title matches, or another error if the lookup fails.
+	LookupByProviderID(ctx context.Context, providerID string, resultType ResultType) (*TitleDetails, error)
+}
+
 // InvalidMaxResultsError is an error type that is returned when the maxResults parameter is invalid.
 type InvalidMaxResultsError struct{}
 
@@ -59,6 +135,7 @@ func (e *InvalidMaxResultsError) Error() string {
 // SearchProviderError is an error type that is returned when the search provider encounters an error.
 type SearchProviderError struct {
 	errorMessage string
+	cause        error
 }
 
 // NewSearchProviderError creates a new SearchProviderError with the specified error message.
@@ -66,11 +143,22 @@ func NewSearchProviderError(errorMessage string) *SearchProviderError {
 	return &SearchProviderError{errorMessage: errorMessage}
 }
 
+// NewSearchProviderErrorFromCause creates a new SearchProviderError for a failed request to the provider,
+// wrapping the error it failed with so that callers can still tell e.g. a cancellation apart.
+func NewSearchProviderErrorFromCause(cause error) *SearchProviderError {
+	return &SearchProviderError{errorMessage: cause.Error(), cause: cause}
+}
+
 // Error returns the error message associated with the SearchProviderError.
 func (e *SearchProviderError) Error() string {
 	return e.errorMessage
 }
 
+// Unwrap returns the error the request to the provider failed with, if any.
+func (e *SearchProviderError) Unwrap() error {
+	return e.cause
+}
+
 // ResultParsingError is an error type that is returned when there is an error parsing the search results.
 type ResultParsingError struct {
 	reason string
@@ -85,3 +173,62 @@ func NewResultParsingError(reason string) *ResultParsingError {
 func (e *ResultParsingError) Error() string {
 	return e.reason
 }
+
+// TitleNotFoundError is an error type that is returned when a lookup does not match any title.
+type TitleNotFoundError struct {
+	id string
+}
+
+// NewTitleNotFoundError creates a new TitleNotFoundError for the specified identifier.
+func NewTitleNotFoundError(id string) *TitleNotFoundError {
+	return &TitleNotFoundError{id: id}
+}
+
+// Error returns the error message associated with the TitleNotFoundError.
+func (e *TitleNotFoundError) Error() string {
+	return fmt.Sprintf("no title found for id %q", e.id)
+}
+
+// RateLimitError is an error type that is returned when a request is refused by a rate limit or quota,
+// whether enforced locally or by the search provider.
+type RateLimitError struct {
+	reason         string
+	quotaExhausted bool
+}
+
+// NewRateLimitError creates a new RateLimitError with the specified reason.
+func NewRateLimitError(reason string) *RateLimitError {
+	return &RateLimitError{reason: reason}
+}
+
+// NewQuotaExhaustedError creates a new RateLimitError with the specified reason for a request refused
+// because a daily quota is exhausted, which unlike a rate limit isn't lifted by waiting a moment.
+func NewQuotaExhaustedError(reason string) *RateLimitError {
+	return &RateLimitError{reason: reason, quotaExhausted: true}
+}
+
+// Error returns the reason associated with the RateLimitError.
+func (e *RateLimitError) Error() string {
+	return e.reason
+}
+
+// QuotaExhausted reports whether the request was refused because a daily quota is exhausted.
+func (e *RateLimitError) QuotaExhausted() bool {
+	return e.quotaExhausted
+}
+
+// InvalidAPIKeyError is an error type that is returned when a provider rejects the API key.
+type InvalidAPIKeyError struct {
+	provider string
+	reason   string
+}
+
+// NewInvalidAPIKeyError creates a new InvalidAPIKeyError for the specified provider and reason.
+func NewInvalidAPIKeyError(provider, reason string) *InvalidAPIKeyError {
+	return &InvalidAPIKeyError{provider: provider, reason: reason}
+}
+
+// Error returns the error message associated with the InvalidAPIKeyError.
+func (e *InvalidAPIKeyError) Error() string {
+	return fmt.Sprintf("%s rejected the API key: %s", e.provider, e.reason)
+}
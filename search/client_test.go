@@ -1,6 +1,11 @@
 package search_test
 
-import "os"
+import (
+	"os"
+	"testing"
+
+	"github.com/jdahan/gogettitles/search"
+)
 
 const (
 	testAPIKey = "testkey"
@@ -9,3 +14,20 @@ const (
 func loadMockResponse(file string) ([]byte, error) {
 	return os.ReadFile("testdata/" + file)
 }
+
+func TestSearchResult_StartYear(t *testing.T) {
+	tests := map[string]string{
+		"1999":      "1999",
+		"2008–2013": "2008",
+		"2005–":     "2005",
+		"":          "",
+		"N/A":       "",
+		"19xx":      "",
+	}
+
+	for year, expected := range tests {
+		if got := (search.SearchResult{Year: year}).StartYear(); got != expected {
+			t.Errorf("expected start year %q for %q, got %q", expected, year, got)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// searchKey identifies equivalent search calls.
+type searchKey struct {
+	query      string
+	maxResults int
+	options    SearchOptions
+}
+
+// newSearchKey builds the key of a search call from its normalized query, maxResults, and the SearchOptions in ctx.
+func newSearchKey(ctx context.Context, query string, maxResults int) searchKey {
+	return searchKey{
+		query:      normalizeQuery(query),
+		maxResults: maxResults,
+		options:    SearchOptionsFromContext(ctx),
+	}
+}
+
+// normalizeQuery lowercases a query and collapses its whitespace, so trivially different queries are equivalent.
+func normalizeQuery(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
+
+// coalescedCall is an upstream search shared by one or more callers.
+type coalescedCall struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	results []SearchResult
+	err     error
+}
+
+// A CoalescingSearcher is a Searcher decorator that collapses concurrent identical searches into a single
+// upstream request whose results are shared between all callers.
+//
+// Searches are identical if their normalized queries, maxResults, and SearchOptions match. A caller whose context
+// is cancelled stops waiting, but the shared request carries on for the remaining callers; it is only cancelled
+// once every caller has gone.
+type CoalescingSearcher struct {
+	searcher Searcher
+
+	mu    sync.Mutex
+	calls map[searchKey]*coalescedCall
+}
+
+// NewCoalescingSearcher creates a new CoalescingSearcher that wraps the specified Searcher.
+//
+// Parameters:
+//   - searcher: The Searcher to send upstream requests to.
+//
+// Returns:
+//   - *CoalescingSearcher: A new instance of CoalescingSearcher.
+func NewCoalescingSearcher(searcher Searcher) *CoalescingSearcher {
+	return &CoalescingSearcher{
+		searcher: searcher,
+		calls:    make(map[searchKey]*coalescedCall),
+	}
+}
+
+// Search performs a search, joining an identical search already in flight if there is one.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - query: The search query string.
+//   - maxResults: The maximum number of search results to return.
+//
+// Returns:
+//   - []SearchResult: A slice containing the search results, owned by the caller.
+//   - error: An error if the search operation fails.
+func (cs *CoalescingSearcher) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	if maxResults <= 0 {
+		return nil, NewInvalidMaxResultsError()
+	}
+
+	key := newSearchKey(ctx, query, maxResults)
+
+	cs.mu.Lock()
+	call, ok := cs.calls[key]
+	if !ok {
+		// The shared request keeps the first caller's values (such as SearchOptions) but not its cancellation
+		sharedCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &coalescedCall{done: make(chan struct{}), cancel: cancel}
+		cs.calls[key] = call
+
+		go cs.do(sharedCtx, key, call, query, maxResults)
+	}
+
+	call.waiters++
+	cs.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return nil, call.err
+		}
+
+		return slices.Clone(call.results), nil
+	case <-ctx.Done():
+		cs.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// Nobody is waiting anymore, so abandon the request and let later callers start afresh
+			call.cancel()
+			cs.forget(key, call)
+		}
+		cs.mu.Unlock()
+
+		return nil, ctx.Err()
+	}
+}
+
+// do performs the shared upstream request and releases its waiters.
+func (cs *CoalescingSearcher) do(ctx context.Context, key searchKey, call *coalescedCall, query string, maxResults int) {
+	defer call.cancel()
+
+	call.results, call.err = cs.searcher.Search(ctx, query, maxResults)
+
+	cs.mu.Lock()
+	cs.forget(key, call)
+	cs.mu.Unlock()
+
+	close(call.done)
+}
+
+// forget removes the call from the in-flight calls, unless it has already been replaced. The caller must hold cs.mu.
+func (cs *CoalescingSearcher) forget(key searchKey, call *coalescedCall) {
+	if cs.calls[key] == call {
+		delete(cs.calls, key)
+	}
+}
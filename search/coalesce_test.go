@@ -0,0 +1,117 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// blockingSearcher blocks every call until release is closed, and counts its calls.
+type blockingSearcher struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (s *blockingSearcher) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	s.calls.Add(1)
+	select {
+	case <-s.release:
+		return []search.SearchResult{{Title: query}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestCoalescingSearcher_Search_CollapsesIdenticalCalls(t *testing.T) {
+	searcher := &blockingSearcher{release: make(chan struct{})}
+	coalescing := search.NewCoalescingSearcher(searcher)
+
+	queries := []string{"star", "Star", "  STAR "}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(queries))
+	for i, query := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = coalescing.Search(context.Background(), query, 5)
+		}()
+	}
+
+	// Give every caller a chance to join the in-flight call before releasing it
+	time.Sleep(20 * time.Millisecond)
+	close(searcher.release)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls := searcher.calls.Load(); calls != 1 {
+		t.Errorf("expected 1 upstream call, got %d", calls)
+	}
+}
+
+func TestCoalescingSearcher_Search_DistinctOptions(t *testing.T) {
+	searcher := &blockingSearcher{release: make(chan struct{})}
+	close(searcher.release)
+	coalescing := search.NewCoalescingSearcher(searcher)
+
+	ctx := search.WithSearchOptions(context.Background(), search.SearchOptions{Language: "de-DE"})
+	if _, err := coalescing.Search(ctx, "star", 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := coalescing.Search(context.Background(), "star", 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls := searcher.calls.Load(); calls != 2 {
+		t.Errorf("expected 2 upstream calls, got %d", calls)
+	}
+}
+
+func TestCoalescingSearcher_Search_CancelledCallerDoesNotCancelOthers(t *testing.T) {
+	searcher := &blockingSearcher{release: make(chan struct{})}
+	coalescing := search.NewCoalescingSearcher(searcher)
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancelledErr := make(chan error, 1)
+	go func() {
+		_, err := coalescing.Search(cancelledCtx, "star", 5)
+		cancelledErr <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	type outcome struct {
+		results []search.SearchResult
+		err     error
+	}
+	survivor := make(chan outcome, 1)
+	go func() {
+		results, err := coalescing.Search(context.Background(), "star", 5)
+		survivor <- outcome{results, err}
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if err := <-cancelledErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled error, got %v", err)
+	}
+
+	close(searcher.release)
+
+	got := <-survivor
+	if got.err != nil || len(got.results) != 1 {
+		t.Fatalf("expected shared results for remaining caller, got %v, %v", got.results, got.err)
+	}
+	if calls := searcher.calls.Load(); calls != 1 {
+		t.Errorf("expected 1 upstream call, got %d", calls)
+	}
+}
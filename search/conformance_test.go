@@ -0,0 +1,139 @@
+package search_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/jdahan/gogettitles/search"
+	"github.com/jdahan/gogettitles/search/searchtest"
+)
+
+// handlerTransport serves requests in-process with a handler, failing them if their context
+// was cancelled while being served, as a real network round trip would.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (ht handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	ht.handler.ServeHTTP(recorder, req)
+
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	return recorder.Result(), nil
+}
+
+// fixturePage returns the results of a fixture on the requested page, calling its OnPage hook.
+func fixturePage(r *http.Request, fixture searchtest.Fixture) []search.SearchResult {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	if fixture.OnPage != nil {
+		fixture.OnPage(page)
+	}
+
+	start := min((page-1)*fixture.PageSize, len(fixture.Results))
+	end := min(start+fixture.PageSize, len(fixture.Results))
+
+	return fixture.Results[start:end]
+}
+
+func TestTmdbSearcher_Conformance(t *testing.T) {
+	searchtest.RunConformance(t, func(t *testing.T, fixture searchtest.Fixture) search.Searcher {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fixture.Fail {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `{"success":false,"status_code":11,"status_message":"Internal error: Something went wrong, contact TMDb."}`)
+				return
+			}
+
+			results := []map[string]any{}
+			for i, result := range fixturePage(r, fixture) {
+				item := map[string]any{"id": i + 1, "poster_path": "/poster.jpg"}
+				if result.Type == search.Series {
+					item["media_type"], item["name"], item["first_air_date"] = "tv", result.Title, result.Year+"-01-20"
+				} else {
+					item["media_type"], item["title"], item["release_date"] = "movie", result.Title, result.Year+"-03-31"
+				}
+				results = append(results, item)
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"page":          r.URL.Query().Get("page"),
+				"results":       results,
+				"total_results": len(fixture.Results),
+				"total_pages":   max(1, (len(fixture.Results)+fixture.PageSize-1)/fixture.PageSize),
+			})
+		})
+
+		return search.NewTmdbSearcher(testAPIKey, &http.Client{Transport: handlerTransport{handler}})
+	})
+}
+
+func TestOmdbSearcher_Conformance(t *testing.T) {
+	searchtest.RunConformance(t, func(t *testing.T, fixture searchtest.Fixture) search.Searcher {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fixture.Fail {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"Response":"False","Error":"Invalid API key!"}`)
+				return
+			}
+
+			page := fixturePage(r, fixture)
+			if len(page) == 0 {
+				fmt.Fprint(w, `{"Response":"False","Error":"Movie not found!"}`)
+				return
+			}
+
+			results := []map[string]any{}
+			for _, result := range page {
+				results = append(results, map[string]any{
+					"Title": result.Title, "Year": result.Year, "imdbID": result.ImdbID, "Type": result.Type, "Poster": "N/A",
+				})
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"Search":       results,
+				"totalResults": strconv.Itoa(len(fixture.Results)),
+				"Response":     "True",
+			})
+		})
+
+		return search.NewOmdbSearcher(testAPIKey, &http.Client{Transport: handlerTransport{handler}})
+	})
+}
+
+func TestTraktSearcher_Conformance(t *testing.T) {
+	searchtest.RunConformance(t, func(t *testing.T, fixture searchtest.Fixture) search.Searcher {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fixture.Fail {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			results := []map[string]any{}
+			for i, result := range fixturePage(r, fixture) {
+				year, _ := strconv.Atoi(result.Year)
+				media := map[string]any{"title": result.Title, "year": year, "ids": map[string]any{"trakt": i + 1, "imdb": result.ImdbID}}
+				if result.Type == search.Series {
+					results = append(results, map[string]any{"type": "show", "show": media})
+				} else {
+					results = append(results, map[string]any{"type": "movie", "movie": media})
+				}
+			}
+
+			w.Header().Set("X-Pagination-Page-Count", strconv.Itoa(max(1, (len(fixture.Results)+fixture.PageSize-1)/fixture.PageSize)))
+			_ = json.NewEncoder(w).Encode(results)
+		})
+
+		return search.NewTraktSearcher(testAPIKey, &http.Client{Transport: handlerTransport{handler}})
+	})
+}
@@ -0,0 +1,255 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+)
+
+// contentFilterMaxOverfetch bounds how many results a ContentFilter requests from the wrapped Searcher,
+// as a multiple of maxResults, when filtering removes results.
+const contentFilterMaxOverfetch = 4
+
+// contentFilterLookupConcurrency bounds how many certifications a ContentFilter looks up at once.
+const contentFilterLookupConcurrency = 4
+
+// FamilySafeRatings are the US certifications suitable for children's profiles.
+var FamilySafeRatings = []string{"G", "PG", "TV-Y", "TV-Y7", "TV-Y7-FV", "TV-G", "TV-PG"}
+
+// A ContentPolicy describes which titles a ContentFilter lets through.
+type ContentPolicy struct {
+	// AllowAdult disables filtering of titles flagged as adult content.
+	AllowAdult bool
+	// AllowedRatings, if non-empty, restricts results to titles whose certification (e.g. OMDB's "Rated") is listed.
+	// Certifications are looked up using Lookuper, which must then be set.
+	AllowedRatings []string
+	// AllowUnrated lets titles without a known certification through when AllowedRatings is set.
+	AllowUnrated bool
+	// Lookuper is used to retrieve the certification of each result when AllowedRatings is set. Results are looked up
+	// by IMDb ID, or by TMDB ID if Lookuper is a TmdbSearcher; results with neither are treated as unrated.
+	Lookuper Lookuper
+}
+
+// FamilySafePolicy returns a ContentPolicy that excludes adult content and any title not rated as family-safe.
+//
+// Parameters:
+//   - lookuper: The Lookuper used to retrieve the certification of each result.
+//
+// Returns:
+//   - ContentPolicy: A policy allowing only FamilySafeRatings.
+func FamilySafePolicy(lookuper Lookuper) ContentPolicy {
+	return ContentPolicy{
+		AllowedRatings: FamilySafeRatings,
+		Lookuper:       lookuper,
+	}
+}
+
+// A ContentFilter is a Searcher decorator that enforces a ContentPolicy on the results of any Searcher.
+// It enables SafeSearch on the wrapped Searcher and filters out any results the policy rejects.
+type ContentFilter struct {
+	searcher Searcher
+	policy   ContentPolicy
+}
+
+// NewContentFilter creates a new ContentFilter that wraps the specified Searcher.
+//
+// Parameters:
+//   - searcher: The Searcher whose results are filtered.
+//   - policy: The ContentPolicy to enforce.
+//
+// Returns:
+//   - *ContentFilter: A new instance of ContentFilter.
+func NewContentFilter(searcher Searcher, policy ContentPolicy) *ContentFilter {
+	return &ContentFilter{
+		searcher: searcher,
+		policy:   policy,
+	}
+}
+
+// Search performs a search using the wrapped Searcher and returns up to maxResults results allowed by the policy.
+// Because results are filtered after they are retrieved, the wrapped Searcher may be asked for more than maxResults.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - query: The search query string.
+//   - maxResults: The maximum number of search results to return.
+//
+// Returns:
+//   - []SearchResult: A slice containing the allowed search results.
+//   - error: An error if the search operation fails.
+func (cf *ContentFilter) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	if maxResults <= 0 {
+		return nil, NewInvalidMaxResultsError()
+	}
+
+	if !cf.policy.AllowAdult {
+		opts := SearchOptionsFromContext(ctx)
+		opts.SafeSearch = true
+		ctx = WithSearchOptions(ctx, opts)
+	}
+
+	// Remember decisions across fetches so each title is looked up at most once
+	decisions := make(map[string]bool)
+
+	for fetch := maxResults; ; fetch *= 2 {
+		results, err := cf.searcher.Search(ctx, query, fetch)
+		if err != nil {
+			return nil, err
+		}
+
+		allowed := make([]SearchResult, 0, maxResults)
+		for next := 0; next < len(results) && len(allowed) < maxResults; {
+			// Only classify as many results as could still be needed, so later ones aren't looked up in vain
+			candidates := results[next:min(len(results), next+maxResults-len(allowed))]
+			next += len(candidates)
+
+			if err := cf.classify(ctx, candidates, decisions); err != nil {
+				return nil, err
+			}
+
+			for _, result := range candidates {
+				if cf.allow(result, decisions) {
+					allowed = append(allowed, result)
+				}
+			}
+		}
+
+		// Stop once we have enough results, the provider is exhausted, or we've fetched as much as we're willing to
+		if len(allowed) == maxResults || len(results) < fetch || fetch >= maxResults*contentFilterMaxOverfetch {
+			return allowed, nil
+		}
+	}
+}
+
+// allow reports whether the policy lets the result through, once classify has decided on its certification.
+//
+// Parameters:
+//   - result: The result to check.
+//   - decisions: The decisions of classify, keyed by title identifier.
+//
+// Returns:
+//   - bool: Whether the result is allowed.
+func (cf *ContentFilter) allow(result SearchResult, decisions map[string]bool) bool {
+	if result.Adult && !cf.policy.AllowAdult {
+		return false
+	}
+
+	if len(cf.policy.AllowedRatings) == 0 {
+		return true
+	}
+
+	return decisions[decisionKey(result)]
+}
+
+// classify looks up the certifications of the results not decided yet, up to contentFilterLookupConcurrency
+// at a time, and records whether the policy allows each of them.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - results: The results to classify.
+//   - decisions: Previous decisions, keyed by title identifier, which the new decisions are added to.
+//
+// Returns:
+//   - error: The context error if ctx is done before every certification is looked up.
+func (cf *ContentFilter) classify(ctx context.Context, results []SearchResult, decisions map[string]bool) error {
+	if len(cf.policy.AllowedRatings) == 0 {
+		return nil
+	}
+
+	var pending []SearchResult
+	queued := make(map[string]bool)
+	for _, result := range results {
+		key := decisionKey(result)
+		if _, ok := decisions[key]; ok || queued[key] || (result.Adult && !cf.policy.AllowAdult) {
+			continue
+		}
+
+		queued[key] = true
+		pending = append(pending, result)
+	}
+
+	indexes := make([]int, len(pending))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	allowed := make([]bool, len(pending))
+	runWorkers(ctx, indexes, contentFilterLookupConcurrency, func(i int) {
+		allowed[i] = cf.decide(ctx, pending[i])
+	}, func(int) {})
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for i, result := range pending {
+		decisions[decisionKey(result)] = allowed[i]
+	}
+
+	return nil
+}
+
+// decide looks up the certification of a result and reports whether the policy allows it.
+// A title whose certification can't be looked up is not allowed.
+func (cf *ContentFilter) decide(ctx context.Context, result SearchResult) bool {
+	details, err := cf.lookup(ctx, result)
+	if err != nil {
+		if ctx.Err() == nil {
+			// Fail closed: a title we can't classify is not shown
+			log.Printf("Excluding result \"%s\": certification lookup failed: %v\n", result.Title, err)
+		}
+		return false
+	}
+
+	var rated string
+	if details != nil {
+		rated = details.Rated
+	}
+
+	var allowed bool
+	switch strings.ToUpper(rated) {
+	case "", "NOT RATED", "UNRATED", "N/A":
+		allowed = cf.policy.AllowUnrated
+	default:
+		allowed = slices.ContainsFunc(cf.policy.AllowedRatings, func(r string) bool {
+			return strings.EqualFold(r, rated)
+		})
+	}
+
+	if details != nil && details.Adult && !cf.policy.AllowAdult {
+		allowed = false
+	}
+
+	return allowed
+}
+
+// decisionKey identifies a title by the IDs its certification is looked up by.
+func decisionKey(result SearchResult) string {
+	return string(result.Type) + "/" + result.ImdbID + "/" + result.TmdbID
+}
+
+// lookup retrieves the details of a result by its IMDb ID, or by its TMDB ID if the Lookuper is TMDB.
+// Other provider IDs are never looked up, since the Lookuper may be of another provider than the result.
+// It returns nil if the title has no ID the Lookuper knows, or the provider does not know the title.
+func (cf *ContentFilter) lookup(ctx context.Context, result SearchResult) (*TitleDetails, error) {
+	if cf.policy.Lookuper == nil {
+		return nil, errors.New("no Lookuper configured for rating filtering")
+	}
+
+	var details *TitleDetails
+	var err error
+	if result.ImdbID != "" {
+		details, err = cf.policy.Lookuper.LookupByImdbID(ctx, result.ImdbID)
+	} else if tmdb, ok := cf.policy.Lookuper.(*TmdbSearcher); ok && result.TmdbID != "" {
+		details, err = tmdb.LookupByProviderID(ctx, result.TmdbID, result.Type)
+	}
+
+	var nfErr *TitleNotFoundError
+	if errors.As(err, &nfErr) {
+		return nil, nil
+	}
+
+	return details, err
+}
@@ -0,0 +1,245 @@
+package search_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/jdahan/gogettitles/search"
+)
+
+// stubSearcher returns a fixed list of results, truncated to maxResults, and records the options it was called with.
+type stubSearcher struct {
+	results []search.SearchResult
+	opts    search.SearchOptions
+	calls   int
+}
+
+func (s *stubSearcher) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	s.calls++
+	s.opts = search.SearchOptionsFromContext(ctx)
+	return s.results[:min(maxResults, len(s.results))], nil
+}
+
+// stubLookuper returns the rating configured for each IMDb ID after latency,
+// and tracks the lookups made and the peak number of concurrent lookups.
+type stubLookuper struct {
+	ratings map[string]string
+	latency time.Duration
+
+	mu      sync.Mutex
+	lookups int
+	active  int
+	peak    int
+}
+
+func (l *stubLookuper) LookupByImdbID(ctx context.Context, imdbID string) (*search.TitleDetails, error) {
+	l.mu.Lock()
+	l.lookups++
+	l.active++
+	l.peak = max(l.peak, l.active)
+	l.mu.Unlock()
+
+	time.Sleep(l.latency)
+
+	l.mu.Lock()
+	l.active--
+	l.mu.Unlock()
+
+	rated, ok := l.ratings[imdbID]
+	if !ok {
+		return nil, search.NewTitleNotFoundError(imdbID)
+	}
+	return &search.TitleDetails{Rated: rated}, nil
+}
+
+func (l *stubLookuper) LookupByProviderID(ctx context.Context, providerID string, resultType search.ResultType) (*search.TitleDetails, error) {
+	return nil, errors.New("unexpected provider ID lookup")
+}
+
+func TestContentFilter_Search_InvalidMaxResults(t *testing.T) {
+	filter := search.NewContentFilter(&stubSearcher{}, search.ContentPolicy{})
+	_, err := filter.Search(context.Background(), "Test", 0)
+	var mrErr *search.InvalidMaxResultsError
+	if err == nil || !errors.As(err, &mrErr) {
+		t.Fatalf("expected invalid max results error, got %v", err)
+	}
+}
+
+func TestContentFilter_Search_ExcludesAdult(t *testing.T) {
+	searcher := &stubSearcher{results: []search.SearchResult{
+		{Title: "Safe", ImdbID: "tt1"},
+		{Title: "Adult", ImdbID: "tt2", Adult: true},
+		{Title: "Also Safe", ImdbID: "tt3"},
+	}}
+
+	filter := search.NewContentFilter(searcher, search.ContentPolicy{})
+	results, err := filter.Search(context.Background(), "Test", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !searcher.opts.SafeSearch {
+		t.Error("expected safe search to be requested upstream")
+	}
+	if len(results) != 2 || results[0].Title != "Safe" || results[1].Title != "Also Safe" {
+		t.Errorf("expected the two safe results, got %+v", results)
+	}
+	if searcher.calls != 2 {
+		t.Errorf("expected the filter to over-fetch once, got %d calls", searcher.calls)
+	}
+}
+
+func TestContentFilter_Search_FamilySafePolicy(t *testing.T) {
+	searcher := &stubSearcher{results: []search.SearchResult{
+		{Title: "Toy Story", ImdbID: "tt0114709"},
+		{Title: "The Matrix", ImdbID: "tt0133093"},
+		{Title: "Unknown", ImdbID: "tt0000000"},
+		{Title: "Bluey", ImdbID: "tt7678620"},
+	}}
+	lookuper := &stubLookuper{ratings: map[string]string{
+		"tt0114709": "G",
+		"tt0133093": "R",
+		"tt7678620": "TV-Y",
+	}}
+
+	filter := search.NewContentFilter(searcher, search.FamilySafePolicy(lookuper))
+	results, err := filter.Search(context.Background(), "Test", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].Title != "Toy Story" || results[1].Title != "Bluey" {
+		t.Errorf("expected only family-safe results, got %+v", results)
+	}
+}
+
+func TestContentFilter_Search_AllowUnrated(t *testing.T) {
+	searcher := &stubSearcher{results: []search.SearchResult{
+		{Title: "Unknown", ImdbID: "tt0000000"},
+	}}
+
+	policy := search.FamilySafePolicy(&stubLookuper{})
+	policy.AllowUnrated = true
+
+	filter := search.NewContentFilter(searcher, policy)
+	results, err := filter.Search(context.Background(), "Test", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected unrated result to be allowed, got %+v", results)
+	}
+}
+
+func TestContentFilter_Search_IgnoresOtherProviderIDs(t *testing.T) {
+	// A Trakt result without an IMDb ID has nothing an OMDB Lookuper can look up
+	searcher := &stubSearcher{results: []search.SearchResult{
+		{Title: "Heat", ProviderId: "481", TmdbID: "949"},
+	}}
+
+	policy := search.FamilySafePolicy(&stubLookuper{})
+	policy.AllowUnrated = true
+
+	filter := search.NewContentFilter(searcher, policy)
+	results, err := filter.Search(context.Background(), "Heat", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected the result to be treated as unrated, got %+v", results)
+	}
+}
+
+func TestContentFilter_Search_ConcurrentLookups(t *testing.T) {
+	searcher := &stubSearcher{}
+	ratings := map[string]string{}
+	for i := range 8 {
+		imdbID := fmt.Sprintf("tt%07d", i)
+		searcher.results = append(searcher.results, search.SearchResult{Title: imdbID, ImdbID: imdbID})
+		ratings[imdbID] = "G"
+	}
+	lookuper := &stubLookuper{ratings: ratings, latency: 10 * time.Millisecond}
+
+	filter := search.NewContentFilter(searcher, search.FamilySafePolicy(lookuper))
+	results, err := filter.Search(context.Background(), "Test", 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 6 {
+		t.Errorf("expected 6 results, got %d", len(results))
+	}
+	// Only the results that could be returned are looked up, a few at a time
+	if lookuper.lookups != 6 || lookuper.peak < 2 || lookuper.peak > 4 {
+		t.Errorf("expected 6 lookups at most 4 at a time, got %d with a peak of %d", lookuper.lookups, lookuper.peak)
+	}
+}
+
+func TestTmdbSearcher_Search_SafeSearch(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Test"
+	serverResponse := `{
+		"results": [
+			{"id": 1, "title": "Safe", "media_type": "movie", "release_date": "2020-01-01", "adult": false},
+			{"id": 2, "title": "Adult", "media_type": "movie", "release_date": "2020-01-01", "adult": true}
+		],
+		"total_results": 2,
+		"total_pages": 1
+	}`
+
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/multi").
+		Get("/").
+		MatchParam("query", query).
+		MatchParam("include_adult", "false").
+		Reply(200).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient, search.WithSafeSearch())
+	results, err := searcher.Search(context.Background(), query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Safe" {
+		t.Errorf("expected adult result to be excluded, got %+v", results)
+	}
+}
+
+func TestTmdbSearcher_Search_SafeSearchIsAFloor(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Test"
+	serverResponse := `{
+		"results": [
+			{"id": 1, "title": "Safe", "media_type": "movie", "release_date": "2020-01-01", "adult": false},
+			{"id": 2, "title": "Adult", "media_type": "movie", "release_date": "2020-01-01", "adult": true}
+		],
+		"total_results": 2,
+		"total_pages": 1
+	}`
+
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/multi").
+		Get("/").
+		MatchParam("query", query).
+		MatchParam("include_adult", "false").
+		Reply(200).
+		JSON(json.RawMessage(serverResponse))
+
+	// A per-call SafeSearch of false doesn't lift the searcher's default
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient, search.WithSafeSearch())
+	ctx := search.WithSearchOptions(context.Background(), search.SearchOptions{SafeSearch: false, Year: "2020"})
+	results, err := searcher.Search(ctx, query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Safe" {
+		t.Errorf("expected adult result to be excluded, got %+v", results)
+	}
+}
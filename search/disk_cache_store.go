@@ -0,0 +1,226 @@
+package search
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// diskCacheExtension is the file extension of entries written by a DiskCacheStore.
+const diskCacheExtension = ".cache"
+
+// DiskCacheStoreConfig holds the configuration of a DiskCacheStore.
+type DiskCacheStoreConfig struct {
+	// MaxEntries is the maximum number of entries kept on disk. Defaults to 10000.
+	MaxEntries int
+	// MaxBytes is the maximum total size of the entries kept on disk. Defaults to 64 MiB.
+	MaxBytes int64
+}
+
+// diskCacheFile is the bookkeeping a DiskCacheStore keeps for each entry on disk.
+type diskCacheFile struct {
+	name     string
+	size     int64
+	accessed time.Time
+}
+
+// A DiskCacheStore is a CacheStore that keeps one file per entry in a directory, so the cache survives restarts.
+// Once the store grows past its bounds, the least recently used entries are evicted.
+//
+// Entries are written atomically, so a DiskCacheStore is safe for concurrent use, and a directory may be shared by
+// several processes; each process only enforces the bounds on the entries it knows about, however.
+type DiskCacheStore struct {
+	dir    string
+	config DiskCacheStoreConfig
+
+	mu         sync.Mutex
+	files      map[string]*diskCacheFile
+	totalBytes int64
+}
+
+// NewDiskCacheStore opens a DiskCacheStore in the specified directory, creating the directory if needed.
+//
+// Parameters:
+//   - dir: The directory to keep entries in.
+//   - config: The configuration of the DiskCacheStore.
+//
+// Returns:
+//   - *DiskCacheStore: A new instance of DiskCacheStore holding the entries already in dir.
+//   - error: An error if the directory could not be created or read.
+func NewDiskCacheStore(dir string, config DiskCacheStoreConfig) (*DiskCacheStore, error) {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 10000
+	}
+
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = 64 << 20
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	ds := &DiskCacheStore{
+		dir:    dir,
+		config: config,
+		files:  make(map[string]*diskCacheFile),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), diskCacheExtension) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		ds.files[entry.Name()] = &diskCacheFile{name: entry.Name(), size: info.Size(), accessed: info.ModTime()}
+		ds.totalBytes += info.Size()
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.evict()
+
+	return ds, nil
+}
+
+// Get returns the entry cached under key. Expired entries, and entries written with another schema version,
+// are deleted and reported as a miss.
+func (ds *DiskCacheStore) Get(ctx context.Context, key string) (*CacheEntry, error) {
+	name := diskCacheFileName(key)
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(ds.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		ds.forget(name)
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := UnmarshalCacheEntry(data)
+	if err != nil || entry == nil || !time.Now().Before(entry.ExpiresAt) {
+		ds.remove(name)
+		return nil, err
+	}
+
+	// Record the access on disk too, so recency survives restarts
+	now := time.Now()
+	if file, ok := ds.files[name]; ok {
+		file.accessed = now
+	} else {
+		ds.files[name] = &diskCacheFile{name: name, size: int64(len(data)), accessed: now}
+		ds.totalBytes += int64(len(data))
+	}
+
+	_ = os.Chtimes(filepath.Join(ds.dir, name), now, now)
+
+	return entry, nil
+}
+
+// Set writes the entry under key, evicting the least recently used entries if the store grows past its bounds.
+func (ds *DiskCacheStore) Set(ctx context.Context, key string, entry *CacheEntry) error {
+	data, err := MarshalCacheEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	name := diskCacheFileName(key)
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	// Write to a temporary file and rename it, so readers never see a partial entry
+	tmp, err := os.CreateTemp(ds.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(ds.dir, name)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	ds.forget(name)
+	ds.files[name] = &diskCacheFile{name: name, size: int64(len(data)), accessed: time.Now()}
+	ds.totalBytes += int64(len(data))
+
+	ds.evict()
+
+	return nil
+}
+
+// evict removes the least recently used entries until the store is within its bounds. The caller must hold ds.mu.
+func (ds *DiskCacheStore) evict() {
+	if len(ds.files) <= ds.config.MaxEntries && ds.totalBytes <= ds.config.MaxBytes {
+		return
+	}
+
+	files := make([]*diskCacheFile, 0, len(ds.files))
+	for _, file := range ds.files {
+		files = append(files, file)
+	}
+
+	slices.SortFunc(files, func(a, b *diskCacheFile) int {
+		return a.accessed.Compare(b.accessed)
+	})
+
+	for _, file := range files {
+		if len(ds.files) <= ds.config.MaxEntries && ds.totalBytes <= ds.config.MaxBytes {
+			return
+		}
+
+		ds.remove(file.name)
+	}
+}
+
+// remove deletes an entry from disk. The caller must hold ds.mu.
+func (ds *DiskCacheStore) remove(name string) {
+	_ = os.Remove(filepath.Join(ds.dir, name))
+	ds.forget(name)
+}
+
+// forget drops the bookkeeping of an entry. The caller must hold ds.mu.
+func (ds *DiskCacheStore) forget(name string) {
+	if file, ok := ds.files[name]; ok {
+		ds.totalBytes -= file.size
+		delete(ds.files, name)
+	}
+}
+
+// diskCacheFileName returns the name of the file holding the entry cached under key.
+func diskCacheFileName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:]) + diskCacheExtension
+}
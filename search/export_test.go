@@ -0,0 +1,19 @@
+package search
+
+// ParseTmdbSearchPage exports parseTmdbSearchPage for the fuzz targets in package search_test.
+func ParseTmdbSearchPage(statusCode int, body []byte, defaultType string) ([]SearchResult, int, int, error) {
+	page, err := parseTmdbSearchPage(statusCode, body, defaultType)
+	return page.Results, page.Listed, page.TotalPages, err
+}
+
+// ParseOmdbSearchPage exports parseOmdbSearchPage for the fuzz targets in package search_test.
+func ParseOmdbSearchPage(body []byte) ([]SearchResult, int, int, error) {
+	page, err := parseOmdbSearchPage(body)
+	return page.Results, page.Listed, page.TotalResults, err
+}
+
+// ParseTraktSearchPage exports parseTraktSearchPage for the fuzz targets in package search_test.
+func ParseTraktSearchPage(statusCode int, pageCount string, body []byte) ([]SearchResult, int, int, error) {
+	page, err := parseTraktSearchPage(statusCode, pageCount, body)
+	return page.Results, page.Listed, page.PageCount, err
+}
@@ -0,0 +1,73 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+// A FallbackSearcher is a Searcher that tries several Searchers in priority order,
+// returning the results of the first one that succeeds.
+//
+// Searchers that report an open circuit (such as a CircuitBreaker) are skipped without being called,
+// so a provider outage degrades to the next provider instantly.
+type FallbackSearcher struct {
+	searchers []Searcher
+}
+
+// NewFallbackSearcher creates a new FallbackSearcher.
+//
+// Parameters:
+//   - searchers: The Searchers to try, in priority order.
+//
+// Returns:
+//   - *FallbackSearcher: A new instance of FallbackSearcher.
+func NewFallbackSearcher(searchers ...Searcher) *FallbackSearcher {
+	return &FallbackSearcher{
+		searchers: searchers,
+	}
+}
+
+// Search performs a search using the first available Searcher, falling back to the next one on failure.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - query: The search query string.
+//   - maxResults: The maximum number of search results to return.
+//
+// Returns:
+//   - []SearchResult: A slice containing the search results.
+//   - error: The last error encountered if every Searcher failed, or a CircuitOpenError if every Searcher was skipped.
+func (fs *FallbackSearcher) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	if maxResults <= 0 {
+		return nil, NewInvalidMaxResultsError()
+	}
+
+	var lastErr error = NewCircuitOpenError()
+
+	for i, searcher := range fs.searchers {
+		if breaker, ok := searcher.(interface{ State() CircuitState }); ok && breaker.State() == CircuitOpen {
+			continue
+		}
+
+		results, err := searcher.Search(ctx, query, maxResults)
+		if err == nil {
+			return results, nil
+		}
+
+		// The remaining searchers would fail the same way once the caller has given up
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, err
+		}
+
+		var mrErr *InvalidMaxResultsError
+		if errors.As(err, &mrErr) {
+			return nil, err
+		}
+
+		log.Printf("Searcher %d failed for query \"%s\", falling back: %v\n", i, query, err)
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
@@ -0,0 +1,256 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+)
+
+// HealthStatus is the health of a provider, as reported by a HealthChecker.
+type HealthStatus int
+
+const (
+	// HealthUnknown is the status of a provider that hasn't been probed yet.
+	HealthUnknown HealthStatus = iota
+	// Healthy means the provider is reachable and accepts the API key.
+	Healthy
+	// HealthInvalidKey means the provider rejects the API key.
+	HealthInvalidKey
+	// HealthQuotaExhausted means the API key's rate limit or daily quota is exhausted.
+	HealthQuotaExhausted
+	// HealthUnreachable means the provider couldn't be reached, or failed to respond properly.
+	HealthUnreachable
+)
+
+// String returns the name of the HealthStatus.
+func (s HealthStatus) String() string {
+	switch s {
+	case Healthy:
+		return "healthy"
+	case HealthInvalidKey:
+		return "invalid key"
+	case HealthQuotaExhausted:
+		return "quota exhausted"
+	case HealthUnreachable:
+		return "unreachable"
+	default:
+		return "unknown"
+	}
+}
+
+// A HealthChecker probes whether a provider is usable.
+type HealthChecker interface {
+	// CheckHealth probes the provider with a single cheap, authenticated request.
+	//
+	// Parameters:
+	//   - ctx: The context for controlling cancellation and deadlines.
+	//
+	// Returns:
+	//   - HealthStatus: The health of the provider.
+	//   - error: The reason the provider isn't healthy, or nil if it is.
+	CheckHealth(ctx context.Context) (HealthStatus, error)
+}
+
+// waitForProbe waits for the rate limiters to admit a health probe, which counts against them like any request.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//
+// Returns:
+//   - HealthStatus: HealthQuotaExhausted if a limiter refuses the probe, or HealthUnreachable if ctx is done first.
+//   - error: The error the rate limiters failed with, or nil once the probe is admitted.
+func (o providerOptions) waitForProbe(ctx context.Context) (HealthStatus, error) {
+	err := o.wait(ctx)
+
+	var rateLimitErr *RateLimitError
+	switch {
+	case err == nil:
+		return Healthy, nil
+	case errors.As(err, &rateLimitErr):
+		return HealthQuotaExhausted, err
+	default:
+		return HealthUnreachable, err
+	}
+}
+
+// ProviderHealth is the outcome of the latest probe of a provider.
+type ProviderHealth struct {
+	// Status is the health of the provider.
+	Status HealthStatus
+	// Err is the reason the provider isn't healthy, if it isn't.
+	Err error
+	// CheckedAt is when the provider was probed, or the zero time if it hasn't been yet.
+	CheckedAt time.Time
+	// Latency is how long the probe took.
+	Latency time.Duration
+}
+
+// HealthMonitorConfig holds the configuration of a HealthMonitor.
+type HealthMonitorConfig struct {
+	// Interval is how often providers are probed. Probes count against the API key's quota, so keep it
+	// well above the quota's period divided by its limit (e.g. OMDB's free tier allows 1000 requests a day).
+	// Defaults to 5 minutes.
+	Interval time.Duration
+	// Timeout bounds each probe. Defaults to 10 seconds.
+	Timeout time.Duration
+	// OnChange, if set, is called when the status of a provider changes, including after its first probe.
+	OnChange func(name string, from, to ProviderHealth)
+}
+
+// A HealthMonitor probes a set of providers in the background and exposes their latest health,
+// e.g. for a readiness endpoint or to decide where to route traffic.
+type HealthMonitor struct {
+	checkers map[string]HealthChecker
+	config   HealthMonitorConfig
+
+	mu     sync.RWMutex
+	health map[string]ProviderHealth
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewHealthMonitor creates a new HealthMonitor and starts probing the providers in the background,
+// starting immediately. Close must be called to stop probing.
+//
+// Parameters:
+//   - checkers: The providers to probe, by name (e.g. "tmdb").
+//   - config: The configuration of the HealthMonitor.
+//
+// Returns:
+//   - *HealthMonitor: A new instance of HealthMonitor.
+func NewHealthMonitor(checkers map[string]HealthChecker, config HealthMonitorConfig) *HealthMonitor {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	hm := &HealthMonitor{
+		checkers: maps.Clone(checkers),
+		config:   config,
+		health:   make(map[string]ProviderHealth, len(checkers)),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go hm.run()
+
+	return hm
+}
+
+// Health returns the latest health of a provider, with status HealthUnknown if it hasn't been probed yet
+// or isn't monitored.
+func (hm *HealthMonitor) Health(name string) ProviderHealth {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+
+	return hm.health[name]
+}
+
+// Statuses returns the latest health of every monitored provider, by name.
+func (hm *HealthMonitor) Statuses() map[string]ProviderHealth {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+
+	statuses := make(map[string]ProviderHealth, len(hm.checkers))
+	for name := range hm.checkers {
+		statuses[name] = hm.health[name]
+	}
+
+	return statuses
+}
+
+// Healthy reports whether every monitored provider was healthy when last probed.
+func (hm *HealthMonitor) Healthy() bool {
+	for _, health := range hm.Statuses() {
+		if health.Status != Healthy {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Check probes every provider now, concurrently, and returns their health.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines; each probe is also bounded by the Timeout.
+//
+// Returns:
+//   - map[string]ProviderHealth: The health of every monitored provider, by name.
+func (hm *HealthMonitor) Check(ctx context.Context) map[string]ProviderHealth {
+	var wg sync.WaitGroup
+	for name, checker := range hm.checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hm.probe(ctx, name, checker)
+		}()
+	}
+
+	wg.Wait()
+
+	return hm.Statuses()
+}
+
+// Close stops probing the providers in the background.
+func (hm *HealthMonitor) Close() {
+	close(hm.stop)
+	<-hm.done
+}
+
+// run probes the providers every interval until the HealthMonitor is closed.
+func (hm *HealthMonitor) run() {
+	defer close(hm.done)
+
+	ticker := time.NewTicker(hm.config.Interval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		<-hm.stop
+		cancel()
+	}()
+
+	for {
+		hm.Check(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-hm.stop:
+			return
+		}
+	}
+}
+
+// probe probes a single provider and records its health.
+// A probe interrupted by the cancellation of ctx, rather than by the Timeout, says nothing about the provider,
+// so it isn't recorded.
+func (hm *HealthMonitor) probe(ctx context.Context, name string, checker HealthChecker) {
+	probeCtx, cancel := context.WithTimeout(ctx, hm.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	status, err := checker.CheckHealth(probeCtx)
+	health := ProviderHealth{Status: status, Err: err, CheckedAt: start, Latency: time.Since(start)}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	hm.mu.Lock()
+	previous := hm.health[name]
+	hm.health[name] = health
+	hm.mu.Unlock()
+
+	if hm.config.OnChange != nil && previous.Status != health.Status {
+		hm.config.OnChange(name, previous, health)
+	}
+}
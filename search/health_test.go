@@ -0,0 +1,350 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/jdahan/gogettitles/search"
+	"github.com/jdahan/gogettitles/search/searchtest"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestTmdbSearcher_CheckHealth(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected search.HealthStatus
+		errCheck func(error) bool
+	}{
+		{
+			name:     "healthy",
+			status:   200,
+			body:     `{"success":true,"status_code":1,"status_message":"Success."}`,
+			expected: search.Healthy,
+		},
+		{
+			name:     "invalid key",
+			status:   401,
+			body:     `{"success":false,"status_code":7,"status_message":"Invalid API key: You must be granted a valid key."}`,
+			expected: search.HealthInvalidKey,
+			errCheck: func(err error) bool {
+				var keyErr *search.InvalidAPIKeyError
+				return errors.As(err, &keyErr)
+			},
+		},
+		{
+			name:     "quota exhausted",
+			status:   429,
+			body:     `{"success":false,"status_code":25,"status_message":"Your request count is over the allowed limit."}`,
+			expected: search.HealthQuotaExhausted,
+			errCheck: func(err error) bool {
+				var rlErr *search.RateLimitError
+				return errors.As(err, &rlErr)
+			},
+		},
+		{
+			name:     "unreachable",
+			status:   500,
+			body:     `{"success":false,"status_code":11,"status_message":"Internal error"}`,
+			expected: search.HealthUnreachable,
+			errCheck: func(err error) bool {
+				var spErr *search.SearchProviderError
+				return errors.As(err, &spErr)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off() // Flush pending mocks after test execution
+
+			gock.New("https://api.themoviedb.org").
+				Get("/3/authentication").
+				MatchHeader("Authorization", "Bearer "+testAPIKey).
+				Reply(tt.status).
+				BodyString(tt.body)
+
+			searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+			status, err := searcher.CheckHealth(context.Background())
+			if status != tt.expected {
+				t.Errorf("expected status %v, got %v", tt.expected, status)
+			}
+
+			if tt.errCheck == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if tt.errCheck != nil && !tt.errCheck(err) {
+				t.Errorf("unexpected error type: %v", err)
+			}
+		})
+	}
+}
+
+func TestOmdbSearcher_CheckHealth(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected search.HealthStatus
+		errCheck func(error) bool
+	}{
+		{
+			name:     "healthy",
+			status:   200,
+			body:     `{"Title":"The Matrix","Year":"1999","imdbID":"tt0133093","Response":"True"}`,
+			expected: search.Healthy,
+		},
+		{
+			name:     "invalid key",
+			status:   401,
+			body:     `{"Response":"False","Error":"Invalid API key!"}`,
+			expected: search.HealthInvalidKey,
+			errCheck: func(err error) bool {
+				var keyErr *search.InvalidAPIKeyError
+				return errors.As(err, &keyErr)
+			},
+		},
+		{
+			name:     "quota exhausted",
+			status:   401,
+			body:     `{"Response":"False","Error":"Request limit reached!"}`,
+			expected: search.HealthQuotaExhausted,
+			errCheck: func(err error) bool {
+				var rlErr *search.RateLimitError
+				return errors.As(err, &rlErr)
+			},
+		},
+		{
+			name:     "unreachable",
+			status:   503,
+			body:     `<html><body>Service Unavailable</body></html>`,
+			expected: search.HealthUnreachable,
+			errCheck: func(err error) bool {
+				var spErr *search.SearchProviderError
+				return errors.As(err, &spErr)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off() // Flush pending mocks after test execution
+
+			gock.New("https://www.omdbapi.com").
+				Get("/").
+				MatchParam("apiKey", testAPIKey).
+				MatchParam("i", "tt0133093").
+				Reply(tt.status).
+				BodyString(tt.body)
+
+			searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient)
+			status, err := searcher.CheckHealth(context.Background())
+			if status != tt.expected {
+				t.Errorf("expected status %v, got %v", tt.expected, status)
+			}
+
+			if tt.errCheck == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if tt.errCheck != nil && !tt.errCheck(err) {
+				t.Errorf("unexpected error type: %v", err)
+			}
+		})
+	}
+}
+
+func TestOmdbSearcher_CheckHealth_QuotaExhausted(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("i", "tt0133093").
+		Reply(200).
+		BodyString(`{"Title":"The Matrix","Year":"1999","imdbID":"tt0133093","Response":"True"}`)
+
+	// The probe counts against the quota like any request
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient, search.WithLimiter(search.NewDailyQuota(1, nil)))
+	if status, err := searcher.CheckHealth(context.Background()); status != search.Healthy || err != nil {
+		t.Fatalf("expected healthy, got %v (%v)", status, err)
+	}
+
+	status, err := searcher.CheckHealth(context.Background())
+	if status != search.HealthQuotaExhausted {
+		t.Errorf("expected quota exhausted, got %v", status)
+	}
+
+	var rlErr *search.RateLimitError
+	if !errors.As(err, &rlErr) {
+		t.Errorf("unexpected error type: %v", err)
+	}
+
+	if !gock.IsDone() {
+		t.Error("expected a single probe to reach OMDB")
+	}
+}
+
+func TestTmdbSearcher_CheckHealth_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A probe that never gets past the rate limiters says nothing about TMDB
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient, search.WithLimiter(search.NewRateLimiter(search.RateLimiterConfig{RequestsPerSecond: 0.001})))
+	status, err := searcher.CheckHealth(ctx)
+	if status != search.HealthUnreachable || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected unreachable with context.Canceled, got %v (%v)", status, err)
+	}
+}
+
+func TestOmdbSearcher_CheckHealth_Tracing(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("i", "tt0133093").
+		MatchHeader("traceparent", "^00-[0-9a-f]{32}-[0-9a-f]{16}-01$").
+		Reply(200).
+		BodyString(`{"Title":"The Matrix","Year":"1999","imdbID":"tt0133093","Response":"True"}`)
+
+	provider, _ := newTestTracerProvider(t)
+	ctx, span := provider.Tracer("test").Start(context.Background(), "probe")
+	defer span.End()
+
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient, search.WithPropagator(propagation.TraceContext{}))
+	if status, err := searcher.CheckHealth(ctx); status != search.Healthy || err != nil {
+		t.Fatalf("expected healthy, got %v (%v)", status, err)
+	}
+}
+
+func TestTmdbSearcher_CheckHealth_FakeProvider(t *testing.T) {
+	server := httptest.NewServer(searchtest.NewFakeProvider(searchtest.FakeProviderConfig{TmdbAPIKey: testAPIKey}))
+	defer server.Close()
+
+	searcher := search.NewTmdbSearcher(testAPIKey, server.Client(), search.WithBaseURL(server.URL))
+	if status, err := searcher.CheckHealth(context.Background()); status != search.Healthy || err != nil {
+		t.Fatalf("expected healthy, got %v (%v)", status, err)
+	}
+
+	searcher = search.NewTmdbSearcher("wrong", server.Client(), search.WithBaseURL(server.URL))
+	if status, _ := searcher.CheckHealth(context.Background()); status != search.HealthInvalidKey {
+		t.Fatalf("expected invalid key, got %v", status)
+	}
+}
+
+// stubChecker is a HealthChecker reporting a settable status.
+type stubChecker struct {
+	mu     sync.Mutex
+	status search.HealthStatus
+}
+
+func (s *stubChecker) set(status search.HealthStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+func (s *stubChecker) CheckHealth(context.Context) (search.HealthStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.status != search.Healthy {
+		return s.status, errors.New(s.status.String())
+	}
+
+	return s.status, nil
+}
+
+func TestHealthMonitor(t *testing.T) {
+	tmdb := &stubChecker{status: search.Healthy}
+	omdb := &stubChecker{status: search.Healthy}
+
+	type change struct {
+		name     string
+		from, to search.HealthStatus
+	}
+	changes := make(chan change, 10)
+
+	monitor := search.NewHealthMonitor(map[string]search.HealthChecker{"tmdb": tmdb, "omdb": omdb}, search.HealthMonitorConfig{
+		Interval: time.Hour,
+		OnChange: func(name string, from, to search.ProviderHealth) {
+			changes <- change{name, from.Status, to.Status}
+		},
+	})
+	defer monitor.Close()
+
+	// Providers are probed immediately
+	for range 2 {
+		select {
+		case c := <-changes:
+			if c.from != search.HealthUnknown || c.to != search.Healthy {
+				t.Errorf("unexpected change for %s: %v -> %v", c.name, c.from, c.to)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected initial probes")
+		}
+	}
+
+	if !monitor.Healthy() {
+		t.Fatal("expected monitor to be healthy")
+	}
+
+	omdb.set(search.HealthQuotaExhausted)
+	statuses := monitor.Check(context.Background())
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 statuses, got %d", len(statuses))
+	}
+
+	health := statuses["omdb"]
+	if health.Status != search.HealthQuotaExhausted || health.Err == nil || health.CheckedAt.IsZero() {
+		t.Errorf("unexpected OMDB health: %+v", health)
+	}
+
+	if monitor.Healthy() {
+		t.Error("expected monitor to be unhealthy")
+	}
+
+	if got := monitor.Health("tmdb").Status; got != search.Healthy {
+		t.Errorf("expected TMDB to be healthy, got %v", got)
+	}
+
+	if got := monitor.Health("trakt").Status; got != search.HealthUnknown {
+		t.Errorf("expected unmonitored provider to be unknown, got %v", got)
+	}
+
+	select {
+	case c := <-changes:
+		if c.name != "omdb" || c.from != search.Healthy || c.to != search.HealthQuotaExhausted {
+			t.Errorf("unexpected change for %s: %v -> %v", c.name, c.from, c.to)
+		}
+	default:
+		t.Error("expected OMDB status change")
+	}
+
+	// An unchanged status isn't reported
+	monitor.Check(context.Background())
+	select {
+	case c := <-changes:
+		t.Errorf("unexpected change for %s: %v -> %v", c.name, c.from, c.to)
+	default:
+	}
+}
+
+func TestHealthStatus_String(t *testing.T) {
+	tests := map[search.HealthStatus]string{
+		search.HealthUnknown:        "unknown",
+		search.Healthy:              "healthy",
+		search.HealthInvalidKey:     "invalid key",
+		search.HealthQuotaExhausted: "quota exhausted",
+		search.HealthUnreachable:    "unreachable",
+	}
+
+	for status, expected := range tests {
+		if got := status.String(); got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Metrics records what searches do, for export to a monitoring system such as Prometheus
+// (see the prommetrics package). Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveSearch records a completed Search call.
+	//
+	// Parameters:
+	//   - provider: The provider or decorator searched, e.g. "tmdb".
+	//   - duration: How long the search took.
+	//   - resultCount: The number of results returned.
+	//   - err: The error the search failed with, if any; see ErrorType.
+	ObserveSearch(provider string, duration time.Duration, resultCount int, err error)
+	// ObservePage records a page requested from a provider. Pages whose request the rate limiters never
+	// admitted aren't recorded.
+	//
+	// Parameters:
+	//   - provider: The provider the page was requested from, e.g. "tmdb".
+	//   - duration: How long the request took, excluding the wait for the rate limiters.
+	//   - err: The error the request failed with, if any; see ErrorType.
+	ObservePage(provider string, duration time.Duration, err error)
+	// ObserveCache records a cache lookup.
+	//
+	// Parameters:
+	//   - cache: The cache looked up, e.g. "cache"; see CachingSearcherConfig.Name.
+	//   - hit: Whether the lookup hit.
+	ObserveCache(cache string, hit bool)
+}
+
+// The types of errors reported by ErrorType.
+const (
+	ErrorTypeProvider    = "provider"
+	ErrorTypeParsing     = "parsing"
+	ErrorTypeRateLimit   = "rate_limit"
+	ErrorTypeCircuitOpen = "circuit_open"
+	ErrorTypeInvalid     = "invalid_request"
+	ErrorTypeCanceled    = "canceled"
+	ErrorTypeOther       = "other"
+)
+
+// ErrorType classifies an error, for use as a metric label.
+//
+// Parameters:
+//   - err: The error to classify.
+//
+// Returns:
+//   - string: One of the ErrorType constants, or an empty string if err is nil.
+func ErrorType(err error) string {
+	var providerErr *SearchProviderError
+	var parsingErr *ResultParsingError
+	var rateLimitErr *RateLimitError
+	var circuitOpenErr *CircuitOpenError
+	var maxResultsErr *InvalidMaxResultsError
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		// Requests to the provider fail with the cancellation wrapped in a SearchProviderError
+		return ErrorTypeCanceled
+	case errors.As(err, &rateLimitErr):
+		return ErrorTypeRateLimit
+	case errors.As(err, &parsingErr):
+		return ErrorTypeParsing
+	case errors.As(err, &providerErr):
+		return ErrorTypeProvider
+	case errors.As(err, &circuitOpenErr):
+		return ErrorTypeCircuitOpen
+	case errors.As(err, &maxResultsErr):
+		return ErrorTypeInvalid
+	default:
+		return ErrorTypeOther
+	}
+}
+
+// noopMetrics is the Metrics implementation used when none is configured.
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveSearch(string, time.Duration, int, error) {}
+func (noopMetrics) ObservePage(string, time.Duration, error)        {}
+func (noopMetrics) ObserveCache(string, bool)                       {}
+
+// metricsOrDefault returns metrics, or a Metrics implementation recording nothing if metrics is nil.
+func metricsOrDefault(metrics Metrics) Metrics {
+	if metrics == nil {
+		return noopMetrics{}
+	}
+
+	return metrics
+}
+
+// WithMetrics sets the Metrics searches are recorded to: every Search call, and every page requested
+// from the provider. Defaults to recording nothing.
+func WithMetrics(metrics Metrics) Option {
+	return func(o *providerOptions) {
+		o.metrics = metrics
+	}
+}
+
+// A MeteredSearcher is a Searcher decorator that records every search to a Metrics, so that any Searcher,
+// including decorators such as a FallbackSearcher or a CircuitBreaker, can be measured under its own name.
+type MeteredSearcher struct {
+	searcher Searcher
+	name     string
+	metrics  Metrics
+}
+
+// NewMeteredSearcher creates a new MeteredSearcher that wraps the specified Searcher.
+//
+// Parameters:
+//   - searcher: The Searcher to measure.
+//   - name: The name searches are recorded under, as their provider.
+//   - metrics: The Metrics searches are recorded to.
+//
+// Returns:
+//   - *MeteredSearcher: A new instance of MeteredSearcher.
+func NewMeteredSearcher(searcher Searcher, name string, metrics Metrics) *MeteredSearcher {
+	return &MeteredSearcher{
+		searcher: searcher,
+		name:     name,
+		metrics:  metricsOrDefault(metrics),
+	}
+}
+
+// Search performs the search and records it.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - query: The search query string.
+//   - maxResults: The maximum number of search results to return.
+//
+// Returns:
+//   - []SearchResult: A slice containing the search results.
+//   - error: An error if the search operation fails.
+func (ms *MeteredSearcher) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	start := time.Now()
+	results, err := ms.searcher.Search(ctx, query, maxResults)
+	ms.metrics.ObserveSearch(ms.name, time.Since(start), len(results), err)
+
+	return results, err
+}
@@ -0,0 +1,35 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+func TestErrorType(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{search.NewSearchProviderError("search request failed"), search.ErrorTypeProvider},
+		{search.NewResultParsingError("unexpected EOF"), search.ErrorTypeParsing},
+		{search.NewRateLimitError("TMDB API request rate limit exceeded"), search.ErrorTypeRateLimit},
+		{fmt.Errorf("batch: %w", search.NewRateLimitError("quota exhausted")), search.ErrorTypeRateLimit},
+		{search.NewCircuitOpenError(), search.ErrorTypeCircuitOpen},
+		{search.NewInvalidMaxResultsError(), search.ErrorTypeInvalid},
+		{context.DeadlineExceeded, search.ErrorTypeCanceled},
+		{search.NewSearchProviderErrorFromCause(&url.Error{Op: "Get", URL: "https://api.themoviedb.org", Err: context.Canceled}), search.ErrorTypeCanceled},
+		{errors.New("boom"), search.ErrorTypeOther},
+	}
+
+	for _, tt := range tests {
+		if got := search.ErrorType(tt.err); got != tt.want {
+			t.Errorf("ErrorType(%v) = %q, expected %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // OmdbConstants holds the constants used for OMDB API requests.
@@ -16,13 +21,121 @@ type OmdbConstants struct {
 	apiKeyParameter string
 	searchParameter string
 	pageParameter   string
+	yearParameter   string
+	typeParameter   string
+	idParameter     string
+	plotParameter   string
 }
 
+// omdbRequestLimitError is the error OMDB returns once the daily quota of an API key is exhausted.
+const omdbRequestLimitError = "Request limit reached!"
+
 var omdbConstants = OmdbConstants{
 	baseURL:         "https://www.omdbapi.com",
 	apiKeyParameter: "apiKey",
 	searchParameter: "s",
 	pageParameter:   "page",
+	yearParameter:   "y",
+	typeParameter:   "type",
+	idParameter:     "i",
+	plotParameter:   "plot",
+}
+
+// omdbSearchResponse is a page of an OMDB search response.
+type omdbSearchResponse struct {
+	Result []struct {
+		Title     string `json:"Title"`
+		Year      string `json:"Year"`
+		ImdbID    string `json:"imdbID"`
+		PosterURL string `json:"Poster"`
+		Type      string `json:"Type"`
+	} `json:"Search"`
+	TotalResults string `json:"totalResults"`
+	Error        string `json:"Error"`
+}
+
+// omdbResultType returns the ResultType of an OMDB type, and whether it is a known type;
+// OMDB also returns other types like "game".
+func omdbResultType(value string) (ResultType, bool) {
+	switch resultType := ResultType(value); resultType {
+	case Movie, Series, Episode:
+		return resultType, true
+	default:
+		return "", false
+	}
+}
+
+// omdbSearchPage is a parsed page of an OMDB search response.
+type omdbSearchPage struct {
+	// Results are the titles on the page; titles of unknown types and malformed ones are skipped.
+	Results []SearchResult
+	// Listed is the number of titles on the page, including skipped ones.
+	Listed int
+	// TotalResults is the total number of titles matching the query, or 0 if none does.
+	TotalResults int
+}
+
+// parseOmdbSearchPage parses a page of an OMDB search response. It performs no I/O, so that it can be fuzzed.
+//
+// Parameters:
+//   - body: The body of the response.
+//
+// Returns:
+//   - omdbSearchPage: The parsed page.
+//   - error: A RateLimitError if the daily quota is exhausted, a ResultParsingError if the body is malformed,
+//     or a SearchProviderError if OMDB reports an error.
+func parseOmdbSearchPage(body []byte) (omdbSearchPage, error) {
+	var omdbResponse omdbSearchResponse
+	if err := json.Unmarshal(body, &omdbResponse); err != nil {
+		return omdbSearchPage{}, NewResultParsingError(err.Error())
+	}
+
+	// Check for TitleNotFound exceptions
+	if omdbResponse.Error == "Movie not found!" {
+		return omdbSearchPage{Results: []SearchResult{}}, nil
+	}
+
+	// Check for an exhausted daily quota
+	if omdbResponse.Error == omdbRequestLimitError {
+		return omdbSearchPage{}, NewQuotaExhaustedError(fmt.Sprintf("OMDB API request failed with error: %s", omdbResponse.Error))
+	}
+
+	// Check for other errors in the response (OMDB API returns an error field if the request fails)
+	if omdbResponse.Error != "" {
+		return omdbSearchPage{}, NewSearchProviderError(fmt.Sprintf("OMDB API request failed with error: %s", omdbResponse.Error))
+	}
+
+	totalResults, err := strconv.Atoi(omdbResponse.TotalResults)
+	if err != nil {
+		return omdbSearchPage{}, NewResultParsingError(fmt.Sprintf("failed to convert totalResults to int: %v", err))
+	}
+
+	if totalResults < 0 {
+		return omdbSearchPage{}, NewResultParsingError(fmt.Sprintf("invalid totalResults: %d", totalResults))
+	}
+
+	page := omdbSearchPage{
+		Results:      make([]SearchResult, 0, len(omdbResponse.Result)),
+		Listed:       len(omdbResponse.Result),
+		TotalResults: totalResults,
+	}
+
+	for _, result := range omdbResponse.Result {
+		resultType, ok := omdbResultType(result.Type)
+		if !ok || result.Title == "" {
+			continue
+		}
+
+		page.Results = append(page.Results, SearchResult{
+			Title:     result.Title,
+			Year:      result.Year,
+			ImdbID:    result.ImdbID,
+			PosterURL: result.PosterURL,
+			Type:      resultType,
+		})
+	}
+
+	return page, nil
 }
 
 // An OMDB-based Searcher implementation.
@@ -31,6 +144,8 @@ type OmdbSearcher struct {
 	apiKey string
 	// The HTTP client to use for making requests.
 	client *http.Client
+	// The defaults applied to every request.
+	options providerOptions
 }
 
 // NewOmdbSearcher creates a new instance of OmdbSearcher with the specified API key and client.
@@ -38,13 +153,15 @@ type OmdbSearcher struct {
 // Parameters:
 //   - apiKey: The OMDB API key to use for searching.
 //   - httpClient: The HTTP client to use for making requests.
+//   - opts: The options configuring the searcher, such as WithLimiter.
 //
 // Returns:
 //   - *OmdbSearcher: A new instance of OmdbSearcher.
-func NewOmdbSearcher(apiKey string, httpClient *http.Client) *OmdbSearcher {
+func NewOmdbSearcher(apiKey string, httpClient *http.Client, opts ...Option) *OmdbSearcher {
 	return &OmdbSearcher{
-		apiKey: apiKey,
-		client: httpClient,
+		apiKey:  apiKey,
+		client:  httpClient,
+		options: newProviderOptions(opts),
 	}
 }
 
@@ -59,18 +176,30 @@ func NewOmdbSearcher(apiKey string, httpClient *http.Client) *OmdbSearcher {
 // Returns:
 //   - []SearchResult: A slice containing the search results.
 //   - error: An error if the search operation fails.
-func (os *OmdbSearcher) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+func (os *OmdbSearcher) Search(ctx context.Context, query string, maxResults int) (results []SearchResult, err error) {
+	ctx, span := os.options.tracer.Start(ctx, "OmdbSearcher.Search", trace.WithAttributes(
+		providerAttribute.String("omdb"),
+		maxResultsAttribute.Int(maxResults),
+	))
+
+	start := time.Now()
+	defer func() {
+		os.options.metrics.ObserveSearch("omdb", time.Since(start), len(results), err)
+		endSpan(span, len(results), err)
+	}()
+
 	if maxResults <= 0 {
 		return nil, NewInvalidMaxResultsError()
 	}
 
-	results := make([]SearchResult, 0, maxResults)
+	results = make([]SearchResult, 0, maxResults)
 
 	// Paginate the search results until we've accumulated maxResults or there are no more results
 	pageNumber := 1
+	listed := 0
 
 	for len(results) < maxResults {
-		nextPageExists, err := os.searchPage(ctx, query, maxResults-len(results), pageNumber, &results)
+		nextPageExists, err := os.searchPage(ctx, query, maxResults-len(results), pageNumber, &results, &listed)
 		if err != nil {
 			return nil, err
 		}
@@ -93,13 +222,29 @@ func (os *OmdbSearcher) Search(ctx context.Context, query string, maxResults int
 //   - maxResults: The maximum number of results to return. Must be greater than 0.
 //   - pageNumber: The page number to retrieve from the OMDB API.
 //   - results: A pointer to a slice of SearchResult where the results will be appended.
+//   - listed: A pointer to the number of titles listed on the previous pages, including skipped ones.
 //
 // Returns:
 //   - bool: A boolean indicating whether there are more pages to retrieve.
 //   - error: An error if the search request failed or the response could not be processed.
-func (os *OmdbSearcher) searchPage(ctx context.Context, query string, maxResults int, pageNumber int, results *[]SearchResult) (bool, error) {
+func (os *OmdbSearcher) searchPage(ctx context.Context, query string, maxResults int, pageNumber int, results *[]SearchResult, listed *int) (nextPage bool, err error) {
+	ctx, span := os.options.tracer.Start(ctx, "OmdbSearcher.searchPage", trace.WithAttributes(
+		providerAttribute.String("omdb"),
+		pageAttribute.Int(pageNumber),
+	))
+
+	// The page is only recorded once the rate limiters admit its request, and timed from then on
+	var start time.Time
+	pageCount := 0
+	defer func() {
+		if !start.IsZero() {
+			os.options.metrics.ObservePage("omdb", time.Since(start), err)
+		}
+		endSpan(span, pageCount, err)
+	}()
+
 	// Build the URL for the search request
-	endpoint, err := url.Parse(omdbConstants.baseURL)
+	endpoint, err := url.Parse(os.options.baseURLOr(omdbConstants.baseURL))
 	if err != nil {
 		return false, err
 	}
@@ -108,6 +253,16 @@ func (os *OmdbSearcher) searchPage(ctx context.Context, query string, maxResults
 	params.Add(omdbConstants.apiKeyParameter, os.apiKey)
 	params.Add(omdbConstants.searchParameter, query)
 	params.Add(omdbConstants.pageParameter, fmt.Sprintf("%d", pageNumber))
+
+	opts := os.options.resolve(ctx)
+	if opts.Year != "" {
+		params.Add(omdbConstants.yearParameter, opts.Year)
+	}
+
+	if opts.Type != "" {
+		params.Add(omdbConstants.typeParameter, string(opts.Type))
+	}
+
 	endpoint.RawQuery = params.Encode()
 
 	// Create the request
@@ -116,68 +271,53 @@ func (os *OmdbSearcher) searchPage(ctx context.Context, query string, maxResults
 		return false, err
 	}
 
+	// Propagate the trace context to the provider
+	os.options.injectTraceContext(ctx, propagation.HeaderCarrier(req.Header))
+
+	// Wait for the rate limiters to admit the request
+	if err := os.options.wait(ctx); err != nil {
+		return false, err
+	}
+
+	start = time.Now()
+
 	// Perform the request
 	resp, err := os.client.Do(req)
 	if err != nil {
-		return false, NewSearchProviderError(err.Error())
+		return false, NewSearchProviderErrorFromCause(err)
 	}
 
 	defer resp.Body.Close()
 
-	// Define the response structure
-	var omdbResponse struct {
-		Result []struct {
-			Title     string     `json:"Title"`
-			Year      string     `json:"Year"`
-			ImdbID    string     `json:"imdbID"`
-			PosterURL string     `json:"Poster"`
-			Type      ResultType `json:"Type"`
-		} `json:"Search"`
-		TotalResults string `json:"totalResults"`
-		Error        string `json:"Error"`
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, NewSearchProviderErrorFromCause(err)
 	}
 
-	// Decode the JSON response
-	if err := json.NewDecoder(resp.Body).Decode(&omdbResponse); err != nil {
-		return false, NewResultParsingError(err.Error())
+	page, err := parseOmdbSearchPage(body)
+	if err != nil {
+		return false, err
 	}
 
-	log.Printf("Found %d results for query \"%s\" on page %d\n", len(omdbResponse.Result), query, pageNumber)
+	pageCount = len(page.Results)
 
-	// Check for TitleNotFound exceptions
-	if omdbResponse.Error == "Movie not found!" {
-		return false, nil
-	}
+	log.Printf("Found %d results for query \"%s\" on page %d\n", len(page.Results), query, pageNumber)
 
-	// Check for other errors in the response (OMDB API returns an error field if the request fails)
-	if omdbResponse.Error != "" {
-		return false, NewSearchProviderError(fmt.Sprintf("OMDB API request failed with error: %s", omdbResponse.Error))
-	}
+	*listed += page.Listed
 
 	// Convert the response to the SearchResult format
-	for _, result := range omdbResponse.Result {
+	for _, result := range page.Results {
 		maxResults--
 
 		if maxResults < 0 {
 			break
 		}
 
-		*results = append(*results, SearchResult{
-			Title:     result.Title,
-			Year:      result.Year,
-			ImdbID:    result.ImdbID,
-			PosterURL: result.PosterURL,
-			Type:      result.Type,
-		})
-	}
-
-	// Check if there are more pages to retrieve
-	totalResults, err := strconv.Atoi(omdbResponse.TotalResults)
-	if err != nil {
-		return false, NewResultParsingError(fmt.Sprintf("failed to convert totalResults to int: %v", err))
+		*results = append(*results, result)
 	}
 
-	if len(*results) < totalResults && maxResults > 0 {
+	// Check if there are more pages to retrieve; an empty page means the total is stale
+	if *listed < page.TotalResults && maxResults > 0 && page.Listed > 0 {
 		return true, nil
 	}
 
@@ -278,3 +278,113 @@ func TestOmdbSearcher_Search_ErrorResponse(t *testing.T) {
 		t.Errorf("expected error containing 'Server error', got %v", err)
 	}
 }
+
+func TestOmdbSearcher_Search_YearAndTypeFilters(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Test"
+	mockData, err := loadMockResponse("omdb_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("s", query).
+		MatchParam("y", "2025").
+		MatchParam("type", "movie").
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient)
+	ctx := search.WithSearchOptions(context.Background(), search.SearchOptions{Year: "2025", Type: search.Movie})
+	if _, err := searcher.Search(ctx, query, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestOmdbSearcher_Search_UnknownType(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Test"
+	serverResponse := `{
+        "Search": [
+            {"Title": "Test Game", "Year": "2020", "imdbID": "tt0000001", "Type": "game", "Poster": "N/A"},
+            {"Title": "Test Movie", "Year": "2021", "imdbID": "tt0000002", "Type": "movie", "Poster": "N/A"}
+        ],
+        "totalResults": "2",
+        "Response": "True"
+    }`
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("apiKey", testAPIKey).
+		MatchParam("s", query).
+		Reply(200).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient)
+	results, err := searcher.Search(context.Background(), query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Type != search.Movie {
+		t.Fatalf("expected only the movie, got %+v", results)
+	}
+}
+
+func TestOmdbSearcher_Search_InvalidTotalResults(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Test"
+	serverResponse := `{
+        "Search": [{"Title": "Test Movie", "Year": "2021", "imdbID": "tt0000002", "Type": "movie"}],
+        "totalResults": "many",
+        "Response": "True"
+    }`
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("apiKey", testAPIKey).
+		MatchParam("s", query).
+		Reply(200).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient)
+	_, err := searcher.Search(context.Background(), query, 5)
+	var rpErr *search.ResultParsingError
+	if err == nil || !errors.As(err, &rpErr) {
+		t.Fatalf("expected result parsing error, got %v", err)
+	}
+}
+
+func TestOmdbSearcher_Search_EmptyPage(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Test"
+	serverResponse := `{
+        "Search": [],
+        "totalResults": "100",
+        "Response": "True"
+    }`
+
+	// Without a stop on empty pages, the searcher would request pages forever
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("apiKey", testAPIKey).
+		MatchParam("s", query).
+		MatchParam("page", "1").
+		Reply(200).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient)
+	results, err := searcher.Search(context.Background(), query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %d", len(results))
+	}
+}
@@ -0,0 +1,81 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// omdbHealthCheckID is the IMDb ID looked up to probe OMDB.
+const omdbHealthCheckID = "tt0133093"
+
+// CheckHealth probes OMDB by looking up a well-known title, which costs a single request.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//
+// Returns:
+//   - HealthStatus: The health of OMDB.
+//   - error: An InvalidAPIKeyError, a RateLimitError, or a SearchProviderError if OMDB isn't healthy,
+//     or the context error if ctx is done while waiting for the rate limiters.
+func (os *OmdbSearcher) CheckHealth(ctx context.Context) (HealthStatus, error) {
+	endpoint, err := url.Parse(os.options.baseURLOr(omdbConstants.baseURL))
+	if err != nil {
+		return HealthUnreachable, err
+	}
+
+	params := url.Values{}
+	params.Add(omdbConstants.apiKeyParameter, os.apiKey)
+	params.Add(omdbConstants.idParameter, omdbHealthCheckID)
+	endpoint.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return HealthUnreachable, err
+	}
+
+	// Propagate the trace context to the provider
+	os.options.injectTraceContext(ctx, propagation.HeaderCarrier(req.Header))
+
+	// Wait for the rate limiters to admit the probe
+	if status, err := os.options.waitForProbe(ctx); err != nil {
+		return status, err
+	}
+
+	resp, err := os.client.Do(req)
+	if err != nil {
+		return HealthUnreachable, NewSearchProviderErrorFromCause(err)
+	}
+
+	defer resp.Body.Close()
+
+	var omdbResponse struct {
+		Response string `json:"Response"`
+		Error    string `json:"Error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&omdbResponse); err != nil {
+		return HealthUnreachable, NewSearchProviderError(fmt.Sprintf("health check failed with status %d", resp.StatusCode))
+	}
+
+	// OMDB reports most errors, including authentication ones, in the body
+	switch omdbResponse.Error {
+	case "":
+		if resp.StatusCode == http.StatusOK {
+			return Healthy, nil
+		}
+	case omdbRequestLimitError:
+		return HealthQuotaExhausted, NewQuotaExhaustedError(fmt.Sprintf("OMDB API request failed with error: %s", omdbResponse.Error))
+	case "Invalid API key!", "No API key provided.":
+		return HealthInvalidKey, NewInvalidAPIKeyError("OMDB", omdbResponse.Error)
+	case "Incorrect IMDb ID.", "Error getting data.":
+		// The key was accepted, even if the title is gone
+		return Healthy, nil
+	}
+
+	return HealthUnreachable, NewSearchProviderError(fmt.Sprintf("health check failed with status %d: %s", resp.StatusCode, omdbResponse.Error))
+}
@@ -0,0 +1,166 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// omdbNotAvailable is the placeholder value OMDB uses for missing fields.
+const omdbNotAvailable = "N/A"
+
+// LookupByImdbID retrieves the details of the title with the given IMDb ID.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - imdbID: The IMDb ID of the title.
+//
+// Returns:
+//   - *TitleDetails: The details of the matching title.
+//   - error: A TitleNotFoundError if no title matches, or another error if the lookup fails.
+func (os *OmdbSearcher) LookupByImdbID(ctx context.Context, imdbID string) (*TitleDetails, error) {
+	// Build the URL for the lookup request
+	endpoint, err := url.Parse(os.options.baseURLOr(omdbConstants.baseURL))
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add(omdbConstants.apiKeyParameter, os.apiKey)
+	params.Add(omdbConstants.idParameter, imdbID)
+	params.Add(omdbConstants.plotParameter, "full")
+	endpoint.RawQuery = params.Encode()
+
+	// Create the request
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for the rate limiters to admit the request
+	if err := os.options.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// Perform the request
+	resp, err := os.client.Do(req)
+	if err != nil {
+		return nil, NewSearchProviderErrorFromCause(err)
+	}
+
+	defer resp.Body.Close()
+
+	// Define the response structure
+	var omdbResponse struct {
+		Title     string `json:"Title"`
+		Year      string `json:"Year"`
+		Rated     string `json:"Rated"`
+		Runtime   string `json:"Runtime"`
+		Genre     string `json:"Genre"`
+		Director  string `json:"Director"`
+		Actors    string `json:"Actors"`
+		Plot      string `json:"Plot"`
+		Language  string `json:"Language"`
+		Country   string `json:"Country"`
+		PosterURL string `json:"Poster"`
+		Ratings   []struct {
+			Source string `json:"Source"`
+			Value  string `json:"Value"`
+		} `json:"Ratings"`
+		ImdbID   string     `json:"imdbID"`
+		Type     ResultType `json:"Type"`
+		Response string     `json:"Response"`
+		Error    string     `json:"Error"`
+	}
+
+	// Decode the JSON response
+	if err := json.NewDecoder(resp.Body).Decode(&omdbResponse); err != nil {
+		return nil, NewResultParsingError(err.Error())
+	}
+
+	// OMDB reports unknown IDs as an error with a successful status code
+	if omdbResponse.Error == "Incorrect IMDb ID." || omdbResponse.Error == "Error getting data." {
+		return nil, NewTitleNotFoundError(imdbID)
+	}
+
+	if omdbResponse.Error == omdbRequestLimitError {
+		return nil, NewQuotaExhaustedError(fmt.Sprintf("OMDB API request failed with error: %s", omdbResponse.Error))
+	}
+
+	if omdbResponse.Error != "" {
+		return nil, NewSearchProviderError(fmt.Sprintf("OMDB API request failed with error: %s", omdbResponse.Error))
+	}
+
+	details := &TitleDetails{
+		SearchResult: SearchResult{
+			Title:     omdbResponse.Title,
+			Year:      omdbResponse.Year,
+			ImdbID:    omdbResponse.ImdbID,
+			PosterURL: omdbField(omdbResponse.PosterURL),
+			Type:      omdbResponse.Type,
+		},
+		Plot:      omdbField(omdbResponse.Plot),
+		Rated:     omdbField(omdbResponse.Rated),
+		Genres:    omdbList(omdbResponse.Genre),
+		Cast:      omdbList(omdbResponse.Actors),
+		Directors: omdbList(omdbResponse.Director),
+		Languages: omdbList(omdbResponse.Language),
+		Countries: omdbList(omdbResponse.Country),
+	}
+
+	// The runtime is formatted as "136 min"
+	if minutes, err := strconv.Atoi(strings.TrimSuffix(omdbResponse.Runtime, " min")); err == nil {
+		details.Runtime = minutes
+	}
+
+	for _, rating := range omdbResponse.Ratings {
+		details.Ratings = append(details.Ratings, Rating{Source: rating.Source, Value: rating.Value})
+	}
+
+	return details, nil
+}
+
+// LookupByProviderID retrieves the details of the title with the given provider-specific ID.
+// OMDB identifies titles by their IMDb ID, so the resultType is ignored.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - providerID: The IMDb ID of the title.
+//   - resultType: Ignored.
+//
+// Returns:
+//   - *TitleDetails: The details of the matching title.
+//   - error: A TitleNotFoundError if no title matches, or another error if the lookup fails.
+func (os *OmdbSearcher) LookupByProviderID(ctx context.Context, providerID string, resultType ResultType) (*TitleDetails, error) {
+	return os.LookupByImdbID(ctx, providerID)
+}
+
+// omdbField returns the value of an OMDB field, or an empty string if the field is not available.
+func omdbField(value string) string {
+	if value == omdbNotAvailable {
+		return ""
+	}
+
+	return value
+}
+
+// omdbList splits a comma-separated OMDB field into its values.
+func omdbList(value string) []string {
+	value = omdbField(value)
+	if value == "" {
+		return nil
+	}
+
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,100 @@
+package search_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/jdahan/gogettitles/search"
+)
+
+func TestOmdbSearcher_LookupByImdbID_Success(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	mockData, err := loadMockResponse("omdb_lookup_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("apiKey", testAPIKey).
+		MatchParam("i", "tt0133093").
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient)
+	details, err := searcher.LookupByImdbID(context.Background(), "tt0133093")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if details.Title != "The Matrix" || details.Year != "1999" || details.Type != search.Movie {
+		t.Errorf("unexpected result: %+v", details.SearchResult)
+	}
+	if details.Runtime != 136 {
+		t.Errorf("expected runtime 136, got %d", details.Runtime)
+	}
+	if details.Rated != "R" {
+		t.Errorf("expected rating R, got %q", details.Rated)
+	}
+	if want := []string{"Lana Wachowski", "Lilly Wachowski"}; !reflect.DeepEqual(details.Directors, want) {
+		t.Errorf("expected directors %v, got %v", want, details.Directors)
+	}
+	if want := []string{"Action", "Sci-Fi"}; !reflect.DeepEqual(details.Genres, want) {
+		t.Errorf("expected genres %v, got %v", want, details.Genres)
+	}
+	if len(details.Cast) != 3 || len(details.Ratings) != 3 || len(details.Countries) != 2 {
+		t.Errorf("unexpected details: %+v", details)
+	}
+}
+
+func TestOmdbSearcher_LookupByProviderID_NotFound(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	serverResponse := `{
+		"Response":"False",
+		"Error":"Incorrect IMDb ID."
+	}`
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("apiKey", testAPIKey).
+		MatchParam("i", "tt0000000").
+		Reply(200).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient)
+	_, err := searcher.LookupByProviderID(context.Background(), "tt0000000", search.Movie)
+	var nfErr *search.TitleNotFoundError
+	if err == nil || !errors.As(err, &nfErr) {
+		t.Fatalf("expected title not found error, got %v", err)
+	}
+}
+
+func TestOmdbSearcher_LookupByImdbID_SearchProviderError(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	serverResponse := `{
+		"Response":"False",
+		"Error":"Invalid API key!"
+	}`
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("apiKey", testAPIKey).
+		MatchParam("i", "tt0133093").
+		Reply(401).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient)
+	_, err := searcher.LookupByImdbID(context.Background(), "tt0133093")
+	var spErr *search.SearchProviderError
+	if err == nil || !errors.As(err, &spErr) {
+		t.Fatalf("expected search provider error, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package search
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SearchOptions holds per-call options that tune how a search is performed.
+// Providers ignore options they do not support.
+type SearchOptions struct {
+	// Language is an ISO 639-1 code, optionally suffixed with an ISO 3166-1 region (e.g. "de" or "de-DE"),
+	// used to localize titles in the results.
+	Language string
+	// Region is an ISO 3166-1 code (e.g. "JP") used to prioritize results released in that region.
+	Region string
+	// SafeSearch asks the provider to exclude adult content, and excludes any flagged results that slip through.
+	// It can only tighten the provider default: false leaves a default set by WithSafeSearch in effect.
+	SafeSearch bool
+	// Year, if set, restricts results to titles released in that year (e.g. "1999").
+	Year string
+	// Type, if set, restricts results to titles of that type.
+	Type ResultType
+}
+
+// searchOptionsKey is the context key under which SearchOptions are stored.
+type searchOptionsKey struct{}
+
+// WithSearchOptions returns a copy of ctx carrying the given SearchOptions.
+// Searchers read these options to override their defaults for a single call.
+func WithSearchOptions(ctx context.Context, opts SearchOptions) context.Context {
+	return context.WithValue(ctx, searchOptionsKey{}, opts)
+}
+
+// SearchOptionsFromContext returns the SearchOptions carried by ctx, or the zero value if there are none.
+func SearchOptionsFromContext(ctx context.Context) SearchOptions {
+	opts, _ := ctx.Value(searchOptionsKey{}).(SearchOptions)
+	return opts
+}
+
+// An Option configures the defaults of a built-in Searcher.
+type Option func(*providerOptions)
+
+// providerOptions holds the defaults shared by the built-in Searcher implementations.
+type providerOptions struct {
+	language   string
+	region     string
+	safeSearch bool
+	limiters   []Limiter
+	baseURL    string
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	metrics    Metrics
+}
+
+// WithLanguage sets the default language used to localize results. See SearchOptions.Language.
+func WithLanguage(language string) Option {
+	return func(o *providerOptions) {
+		o.language = language
+	}
+}
+
+// WithRegion sets the default region used to prioritize results. See SearchOptions.Region.
+func WithRegion(region string) Option {
+	return func(o *providerOptions) {
+		o.region = region
+	}
+}
+
+// WithSafeSearch enables safe search for every call. It is a hard floor rather than an overridable default:
+// a per-call SearchOptions.SafeSearch of false doesn't turn it off, so a single call can't opt into adult
+// content. Use separate searchers if some calls must include it. See SearchOptions.SafeSearch.
+func WithSafeSearch() Option {
+	return func(o *providerOptions) {
+		o.safeSearch = true
+	}
+}
+
+// WithBaseURL sets the base URL of the provider's API (e.g. "http://127.0.0.1:8080"),
+// such as a fake provider in tests or a caching proxy.
+func WithBaseURL(baseURL string) Option {
+	return func(o *providerOptions) {
+		o.baseURL = baseURL
+	}
+}
+
+// newProviderOptions applies the given options over the zero defaults.
+func newProviderOptions(opts []Option) providerOptions {
+	var o providerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.tracer == nil {
+		o.tracer = tracerOrDefault(nil)
+	}
+
+	o.metrics = metricsOrDefault(o.metrics)
+
+	return o
+}
+
+// baseURLOr returns the configured base URL of the provider's API, or defaultURL if none is configured.
+func (o providerOptions) baseURLOr(defaultURL string) string {
+	if o.baseURL != "" {
+		return o.baseURL
+	}
+
+	return defaultURL
+}
+
+// resolve merges the per-call SearchOptions carried by ctx over the provider defaults.
+func (o providerOptions) resolve(ctx context.Context) SearchOptions {
+	opts := SearchOptionsFromContext(ctx)
+	if opts.Language == "" {
+		opts.Language = o.language
+	}
+
+	if opts.Region == "" {
+		opts.Region = o.region
+	}
+
+	// Safe search is a floor, so a per-call false can't lift the provider default
+	opts.SafeSearch = opts.SafeSearch || o.safeSearch
+
+	return opts
+}
+
+// matches reports whether a result satisfies the Year and Type filters of the options.
+func (opts SearchOptions) matches(result SearchResult) bool {
+	if opts.Year != "" && result.Year != opts.Year {
+		return false
+	}
+
+	if opts.Type != "" && result.Type != opts.Type {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,146 @@
+package search_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// checkParsed fails the test if a parser returned an error of an unexpected type or an invalid result.
+func checkParsed(t *testing.T, results []search.SearchResult, err error) {
+	t.Helper()
+
+	if err != nil {
+		var rpErr *search.ResultParsingError
+		var spErr *search.SearchProviderError
+		var rlErr *search.RateLimitError
+		if !errors.As(err, &rpErr) && !errors.As(err, &spErr) && !errors.As(err, &rlErr) {
+			t.Fatalf("unexpected error type %T: %v", err, err)
+		}
+
+		if results != nil {
+			t.Fatalf("expected no results with error %v, got %d", err, len(results))
+		}
+
+		return
+	}
+
+	for _, result := range results {
+		switch result.Type {
+		case search.Movie, search.Series, search.Episode:
+		default:
+			t.Fatalf("invalid result type %q in %+v", result.Type, result)
+		}
+
+		if result.Title == "" {
+			t.Fatalf("result without title: %+v", result)
+		}
+	}
+}
+
+func FuzzParseTmdbSearchPage(f *testing.F) {
+	for _, name := range []string{"tmdb_response.json", "tmdb_paginated_response_1.json", "tmdb_trending_response.json"} {
+		data, err := loadMockResponse(name)
+		if err != nil {
+			f.Fatalf("failed to load mock response: %v", err)
+		}
+
+		f.Add(http.StatusOK, data)
+	}
+
+	f.Add(http.StatusOK, []byte(`{"results":[{"title":"Short","media_type":"movie","release_date":"19"}],"total_pages":1}`))
+	f.Add(http.StatusOK, []byte(`{"results":[{"name":"Garbage","media_type":"tv","first_air_date":"abcd-01-01"}],"total_pages":-1}`))
+	f.Add(http.StatusUnauthorized, []byte(`{"success":false,"status_code":7,"status_message":"Invalid API key"}`))
+	f.Add(http.StatusTooManyRequests, []byte(`not json`))
+
+	f.Fuzz(func(t *testing.T, statusCode int, body []byte) {
+		results, listed, totalPages, err := search.ParseTmdbSearchPage(statusCode, body, "")
+		checkParsed(t, results, err)
+
+		if totalPages < 0 || listed < len(results) {
+			t.Fatalf("invalid counts: %d results, %d listed, %d pages", len(results), listed, totalPages)
+		}
+
+		for _, result := range results {
+			if result.Type == search.Episode {
+				t.Fatalf("unexpected episode from a multi search: %+v", result)
+			}
+
+			if result.Year == "" {
+				continue
+			}
+
+			if len(result.Year) != 4 {
+				t.Fatalf("invalid year %q", result.Year)
+			}
+
+			for _, c := range result.Year {
+				if c < '0' || c > '9' {
+					t.Fatalf("invalid year %q", result.Year)
+				}
+			}
+		}
+	})
+}
+
+func FuzzParseOmdbSearchPage(f *testing.F) {
+	for _, name := range []string{"omdb_response.json", "omdb_paginated_response_1.json", "omdb_paginated_response_2.json"} {
+		data, err := loadMockResponse(name)
+		if err != nil {
+			f.Fatalf("failed to load mock response: %v", err)
+		}
+
+		f.Add(data)
+	}
+
+	f.Add([]byte(`{"Search":[{"Title":"Game","Type":"game"}],"totalResults":"1","Response":"True"}`))
+	f.Add([]byte(`{"Search":[],"totalResults":"many","Response":"True"}`))
+	f.Add([]byte(`{"Response":"False","Error":"Movie not found!"}`))
+	f.Add([]byte(`{"Response":"False","Error":"Request limit reached!"}`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		results, listed, totalResults, err := search.ParseOmdbSearchPage(body)
+		checkParsed(t, results, err)
+
+		if totalResults < 0 || listed < len(results) {
+			t.Fatalf("invalid counts: %d results, %d listed, %d in total", len(results), listed, totalResults)
+		}
+	})
+}
+
+func FuzzParseTraktSearchPage(f *testing.F) {
+	for _, name := range []string{"trakt_response.json", "trakt_paginated_response_1.json", "trakt_paginated_response_2.json"} {
+		data, err := loadMockResponse(name)
+		if err != nil {
+			f.Fatalf("failed to load mock response: %v", err)
+		}
+
+		f.Add(http.StatusOK, "2", data)
+	}
+
+	f.Add(http.StatusOK, "", []byte(`[{"type":"movie","movie":{"title":"Unknown","year":null,"ids":{"trakt":1,"imdb":null,"tmdb":null}}}]`))
+	f.Add(http.StatusOK, "-1", []byte(`[{"type":"person","person":{"name":"Keanu Reeves"}},{"type":"show"}]`))
+	f.Add(http.StatusForbidden, "", []byte(``))
+	f.Add(http.StatusTooManyRequests, "", []byte(`not json`))
+
+	f.Fuzz(func(t *testing.T, statusCode int, pageCount string, body []byte) {
+		results, listed, totalPages, err := search.ParseTraktSearchPage(statusCode, pageCount, body)
+		checkParsed(t, results, err)
+
+		if totalPages < 0 || listed < len(results) {
+			t.Fatalf("invalid counts: %d results, %d listed, %d pages", len(results), listed, totalPages)
+		}
+
+		for _, result := range results {
+			if result.Type == search.Episode {
+				t.Fatalf("unexpected episode from a movie and show search: %+v", result)
+			}
+
+			if result.ProviderId == "" {
+				t.Fatalf("result without provider ID: %+v", result)
+			}
+		}
+	})
+}
@@ -0,0 +1,207 @@
+// Package prommetrics implements search.Metrics with Prometheus collectors, so searches can be monitored
+// alongside the rest of a service.
+package prommetrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jdahan/gogettitles/search"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Config holds the configuration of a Metrics.
+type Config struct {
+	// Namespace prefixes the name of every metric. Defaults to "gogettitles".
+	Namespace string
+	// DurationBuckets are the buckets of the latency histograms, in seconds. Defaults to prometheus.DefBuckets.
+	DurationBuckets []float64
+	// ResultBuckets are the buckets of the results-per-search histogram. Defaults to 0, 1, 2, 5, 10, 20, 50, and 100.
+	ResultBuckets []float64
+}
+
+// A Metrics is a search.Metrics exporting the following metrics, prefixed with the namespace:
+//
+//   - search_requests_total{provider}: The number of searches.
+//   - search_pages_total{provider}: The number of pages requested from providers.
+//   - search_duration_seconds{provider}: A histogram of search latencies.
+//   - search_page_duration_seconds{provider}: A histogram of page request latencies.
+//   - search_errors_total{provider,type}: The number of failed searches, by search.ErrorType.
+//   - search_results{provider}: A histogram of the number of results of successful searches.
+//   - cache_lookups_total{cache,result}: The number of cache lookups, with result "hit" or "miss".
+//   - cache_hit_ratio{cache}: The ratio of cache lookups that hit since startup. For a windowed ratio, divide
+//     the rates of cache_lookups_total{result="hit"} and cache_lookups_total instead.
+type Metrics struct {
+	requests      *prometheus.CounterVec
+	pages         *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	pageDuration  *prometheus.HistogramVec
+	errors        *prometheus.CounterVec
+	results       *prometheus.HistogramVec
+	cacheLookups  *prometheus.CounterVec
+	cacheHitRatio *hitRatioCollector
+}
+
+// New creates a new Metrics and registers its collectors.
+//
+// Parameters:
+//   - registerer: The registry to register the collectors with, such as prometheus.DefaultRegisterer.
+//   - config: The configuration of the Metrics.
+//
+// Returns:
+//   - *Metrics: A new instance of Metrics.
+//   - error: An error if a collector cannot be registered, e.g. because one with the same name already is.
+func New(registerer prometheus.Registerer, config Config) (*Metrics, error) {
+	if config.Namespace == "" {
+		config.Namespace = "gogettitles"
+	}
+
+	if config.DurationBuckets == nil {
+		config.DurationBuckets = prometheus.DefBuckets
+	}
+
+	if config.ResultBuckets == nil {
+		config.ResultBuckets = []float64{0, 1, 2, 5, 10, 20, 50, 100}
+	}
+
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "search_requests_total",
+			Help:      "The number of searches.",
+		}, []string{"provider"}),
+		pages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "search_pages_total",
+			Help:      "The number of pages requested from providers.",
+		}, []string{"provider"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.Namespace,
+			Name:      "search_duration_seconds",
+			Help:      "The latency of searches.",
+			Buckets:   config.DurationBuckets,
+		}, []string{"provider"}),
+		pageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.Namespace,
+			Name:      "search_page_duration_seconds",
+			Help:      "The latency of page requests to providers.",
+			Buckets:   config.DurationBuckets,
+		}, []string{"provider"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "search_errors_total",
+			Help:      "The number of failed searches, by error type.",
+		}, []string{"provider", "type"}),
+		results: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.Namespace,
+			Name:      "search_results",
+			Help:      "The number of results of successful searches.",
+			Buckets:   config.ResultBuckets,
+		}, []string{"provider"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "cache_lookups_total",
+			Help:      "The number of cache lookups, by result.",
+		}, []string{"cache", "result"}),
+		cacheHitRatio: &hitRatioCollector{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(config.Namespace, "", "cache_hit_ratio"),
+				"The ratio of cache lookups that hit since startup.",
+				[]string{"cache"}, nil,
+			),
+		},
+	}
+
+	for _, collector := range []prometheus.Collector{
+		m.requests, m.pages, m.duration, m.pageDuration, m.errors, m.results, m.cacheLookups, m.cacheHitRatio,
+	} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// ObserveSearch records a completed search.
+func (m *Metrics) ObserveSearch(provider string, duration time.Duration, resultCount int, err error) {
+	m.requests.WithLabelValues(provider).Inc()
+	m.duration.WithLabelValues(provider).Observe(duration.Seconds())
+
+	if err != nil {
+		m.errors.WithLabelValues(provider, search.ErrorType(err)).Inc()
+		return
+	}
+
+	m.results.WithLabelValues(provider).Observe(float64(resultCount))
+}
+
+// ObservePage records a page requested from a provider. Failed pages fail their search, so errors are
+// counted by ObserveSearch rather than here.
+func (m *Metrics) ObservePage(provider string, duration time.Duration, err error) {
+	m.pages.WithLabelValues(provider).Inc()
+	m.pageDuration.WithLabelValues(provider).Observe(duration.Seconds())
+}
+
+// ObserveCache records a cache lookup.
+func (m *Metrics) ObserveCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	m.cacheHitRatio.observe(cache, hit)
+	m.cacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+// cacheCounts are the lookups of a cache since startup.
+type cacheCounts struct {
+	hits, lookups atomic.Int64
+}
+
+// hitRatioCollector exports the ratio of lookups that hit of every cache, computed when collected.
+type hitRatioCollector struct {
+	desc *prometheus.Desc
+	// caches maps the name of each cache to its *cacheCounts.
+	caches sync.Map
+}
+
+// observe counts a lookup of a cache.
+func (c *hitRatioCollector) observe(cache string, hit bool) {
+	value, ok := c.caches.Load(cache)
+	if !ok {
+		value, _ = c.caches.LoadOrStore(cache, &cacheCounts{})
+	}
+
+	counts := value.(*cacheCounts)
+	if hit {
+		counts.hits.Add(1)
+	}
+
+	counts.lookups.Add(1)
+}
+
+// Describe implements prometheus.Collector.
+func (c *hitRatioCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector.
+func (c *hitRatioCollector) Collect(ch chan<- prometheus.Metric) {
+	c.caches.Range(func(key, value any) bool {
+		counts := value.(*cacheCounts)
+
+		// Read the lookups first, so that a concurrent hit never pushes the ratio above 1
+		lookups := counts.lookups.Load()
+		hits := min(counts.hits.Load(), lookups)
+
+		ratio := 0.0
+		if lookups > 0 {
+			ratio = float64(hits) / float64(lookups)
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, ratio, key.(string))
+		return true
+	})
+}
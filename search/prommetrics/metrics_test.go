@@ -0,0 +1,251 @@
+package prommetrics_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jdahan/gogettitles/search"
+	"github.com/jdahan/gogettitles/search/prommetrics"
+	"github.com/jdahan/gogettitles/search/searchtest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func newTestMetrics(t *testing.T) (*prommetrics.Metrics, *prometheus.Registry) {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	metrics, err := prommetrics.New(registry, prommetrics.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return metrics, registry
+}
+
+// histogramCount returns the number of observations of a histogram with the given labels.
+func histogramCount(t *testing.T, registry *prometheus.Registry, name string, labels map[string]string) uint64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, pair := range metric.GetLabel() {
+				if labels[pair.GetName()] != pair.GetValue() {
+					continue metrics
+				}
+			}
+
+			return metric.GetHistogram().GetSampleCount()
+		}
+	}
+
+	return 0
+}
+
+func TestMetrics_Searchers(t *testing.T) {
+	metrics, registry := newTestMetrics(t)
+
+	provider := searchtest.NewFakeProvider(searchtest.FakeProviderConfig{TmdbPageSize: 2, OmdbPageSize: 2})
+	server := httptest.NewServer(provider)
+	defer server.Close()
+
+	tmdb := search.NewTmdbSearcher("key", server.Client(), search.WithBaseURL(server.URL), search.WithMetrics(metrics))
+	omdb := search.NewOmdbSearcher("key", server.Client(), search.WithBaseURL(server.URL), search.WithMetrics(metrics))
+
+	// "matrix" matches the 3 Matrix movies, over 2 pages
+	if _, err := tmdb.Search(context.Background(), "matrix", 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	provider.Inject(http.StatusTooManyRequests, 1)
+	if _, err := tmdb.Search(context.Background(), "matrix", 10); err == nil {
+		t.Fatal("expected rate limit error")
+	}
+
+	provider.Inject(http.StatusInternalServerError, 1)
+	if _, err := omdb.Search(context.Background(), "matrix", 10); err == nil {
+		t.Fatal("expected provider error")
+	}
+
+	expected := `
+# HELP gogettitles_search_errors_total The number of failed searches, by error type.
+# TYPE gogettitles_search_errors_total counter
+gogettitles_search_errors_total{provider="omdb",type="provider"} 1
+gogettitles_search_errors_total{provider="tmdb",type="rate_limit"} 1
+# HELP gogettitles_search_pages_total The number of pages requested from providers.
+# TYPE gogettitles_search_pages_total counter
+gogettitles_search_pages_total{provider="omdb"} 1
+gogettitles_search_pages_total{provider="tmdb"} 3
+# HELP gogettitles_search_requests_total The number of searches.
+# TYPE gogettitles_search_requests_total counter
+gogettitles_search_requests_total{provider="omdb"} 1
+gogettitles_search_requests_total{provider="tmdb"} 2
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"gogettitles_search_errors_total", "gogettitles_search_pages_total", "gogettitles_search_requests_total"); err != nil {
+		t.Error(err)
+	}
+
+	if got := histogramCount(t, registry, "gogettitles_search_duration_seconds", map[string]string{"provider": "tmdb"}); got != 2 {
+		t.Errorf("expected 2 TMDB search latencies, got %d", got)
+	}
+
+	if got := histogramCount(t, registry, "gogettitles_search_page_duration_seconds", map[string]string{"provider": "tmdb"}); got != 3 {
+		t.Errorf("expected 3 TMDB page latencies, got %d", got)
+	}
+
+	// Only successful searches are counted in the results histogram
+	if got := histogramCount(t, registry, "gogettitles_search_results", map[string]string{"provider": "tmdb"}); got != 1 {
+		t.Errorf("expected 1 TMDB result count, got %d", got)
+	}
+}
+
+func TestMetrics_Cache(t *testing.T) {
+	metrics, registry := newTestMetrics(t)
+
+	// Caches sharing a Metrics are told apart by name
+	titles := search.NewCachingSearcher(searchtest.NewFakeSearcher(nil), search.CachingSearcherConfig{Metrics: metrics})
+	trending := search.NewCachingSearcher(searchtest.NewFakeSearcher(nil), search.CachingSearcherConfig{Metrics: metrics, Name: "trending"})
+
+	for _, query := range []string{"matrix", "matrix", "matrix", "heat"} {
+		if _, err := titles.Search(context.Background(), query, 5); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for _, query := range []string{"matrix", "heat"} {
+		if _, err := trending.Search(context.Background(), query, 5); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := `
+# HELP gogettitles_cache_hit_ratio The ratio of cache lookups that hit since startup.
+# TYPE gogettitles_cache_hit_ratio gauge
+gogettitles_cache_hit_ratio{cache="cache"} 0.5
+gogettitles_cache_hit_ratio{cache="trending"} 0
+# HELP gogettitles_cache_lookups_total The number of cache lookups, by result.
+# TYPE gogettitles_cache_lookups_total counter
+gogettitles_cache_lookups_total{cache="cache",result="hit"} 2
+gogettitles_cache_lookups_total{cache="cache",result="miss"} 2
+gogettitles_cache_lookups_total{cache="trending",result="miss"} 2
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"gogettitles_cache_hit_ratio", "gogettitles_cache_lookups_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+// delayLimiter is a search.Limiter admitting every request after a delay, or refusing every request.
+type delayLimiter struct {
+	delay  time.Duration
+	refuse bool
+}
+
+func (l delayLimiter) Wait(ctx context.Context) error {
+	if l.refuse {
+		return search.NewRateLimitError("refused")
+	}
+
+	time.Sleep(l.delay)
+	return nil
+}
+
+func TestMetrics_PagesExcludeRateLimiting(t *testing.T) {
+	metrics, registry := newTestMetrics(t)
+
+	server := httptest.NewServer(searchtest.NewFakeProvider(searchtest.FakeProviderConfig{}))
+	defer server.Close()
+
+	delay := 200 * time.Millisecond
+	slow := search.NewTmdbSearcher("key", server.Client(), search.WithBaseURL(server.URL),
+		search.WithLimiter(delayLimiter{delay: delay}), search.WithMetrics(metrics))
+	if _, err := slow.Search(context.Background(), "matrix", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Pages never admitted by the rate limiters weren't requested
+	refused := search.NewTmdbSearcher("key", server.Client(), search.WithBaseURL(server.URL),
+		search.WithLimiter(delayLimiter{refuse: true}), search.WithMetrics(metrics))
+	if _, err := refused.Search(context.Background(), "matrix", 1); err == nil {
+		t.Fatal("expected rate limit error")
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != "gogettitles_search_page_duration_seconds" {
+			continue
+		}
+
+		histogram := family.GetMetric()[0].GetHistogram()
+		if histogram.GetSampleCount() != 1 {
+			t.Errorf("expected 1 page latency, got %d", histogram.GetSampleCount())
+		}
+
+		if histogram.GetSampleSum() >= delay.Seconds() {
+			t.Errorf("expected the page latency to exclude the %v wait, got %vs", delay, histogram.GetSampleSum())
+		}
+	}
+}
+
+func TestMetrics_MeteredSearcher(t *testing.T) {
+	metrics, registry := newTestMetrics(t)
+
+	fake := searchtest.NewFakeSearcher(nil)
+	fake.FailNext(nil, search.NewResultParsingError("bad JSON"), search.NewCircuitOpenError())
+	searcher := search.NewMeteredSearcher(fake, "fallback", metrics)
+
+	for range 3 {
+		_, _ = searcher.Search(context.Background(), "matrix", 5)
+	}
+
+	expected := `
+# HELP gogettitles_search_errors_total The number of failed searches, by error type.
+# TYPE gogettitles_search_errors_total counter
+gogettitles_search_errors_total{provider="fallback",type="circuit_open"} 1
+gogettitles_search_errors_total{provider="fallback",type="parsing"} 1
+# HELP gogettitles_search_requests_total The number of searches.
+# TYPE gogettitles_search_requests_total counter
+gogettitles_search_requests_total{provider="fallback"} 3
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"gogettitles_search_errors_total", "gogettitles_search_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNew_DuplicateRegistration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := prommetrics.New(registry, prommetrics.Config{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var registeredErr prometheus.AlreadyRegisteredError
+	if _, err := prommetrics.New(registry, prommetrics.Config{}); !errors.As(err, &registeredErr) {
+		t.Fatalf("expected already registered error, got %v", err)
+	}
+
+	// Another namespace doesn't collide
+	if _, err := prommetrics.New(registry, prommetrics.Config{Namespace: "other", DurationBuckets: []float64{time.Second.Seconds()}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
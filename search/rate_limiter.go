@@ -0,0 +1,238 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// A Limiter gates the requests a Searcher sends to its provider.
+// Limiters are safe for concurrent use and may be shared between several Searchers.
+type Limiter interface {
+	// Wait blocks until a request may be sent, or returns an error if it may not.
+	//
+	// Parameters:
+	//   - ctx: The context for controlling cancellation and deadlines.
+	//
+	// Returns:
+	//   - error: A RateLimitError if the request is refused, or the context error if ctx is done first.
+	Wait(ctx context.Context) error
+}
+
+// WithLimiter gates every request sent to the provider with the specified Limiter.
+// It may be given several times, in which case every Limiter must admit the request. DailyQuotas are
+// checked after the other limiters, so requests those refuse don't count against the quota.
+func WithLimiter(limiter Limiter) Option {
+	return func(o *providerOptions) {
+		o.limiters = append(o.limiters, limiter)
+	}
+}
+
+// RateLimiterConfig holds the configuration of a RateLimiter.
+type RateLimiterConfig struct {
+	// RequestsPerSecond is the rate at which the bucket refills.
+	RequestsPerSecond float64
+	// Burst is the capacity of the bucket, i.e. the number of requests that may be sent at once. Defaults to 1.
+	Burst int
+	// FailFast makes Wait return a RateLimitError immediately instead of blocking until a token is available.
+	FailFast bool
+}
+
+// A RateLimiter is a token-bucket Limiter.
+type RateLimiter struct {
+	config RateLimiterConfig
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter with a full bucket.
+//
+// Parameters:
+//   - config: The configuration of the RateLimiter.
+//
+// Returns:
+//   - *RateLimiter: A new instance of RateLimiter.
+func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
+	if config.Burst <= 0 {
+		config.Burst = 1
+	}
+
+	return &RateLimiter{
+		config: config,
+		tokens: float64(config.Burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait takes a token from the bucket, blocking until one is available unless the RateLimiter fails fast.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//
+// Returns:
+//   - error: A RateLimitError if no token is available and the RateLimiter fails fast, or the context error.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rl.mu.Lock()
+
+	// Refill the bucket for the time elapsed since the last request
+	now := time.Now()
+	rl.tokens = min(float64(rl.config.Burst), rl.tokens+now.Sub(rl.last).Seconds()*rl.config.RequestsPerSecond)
+	rl.last = now
+
+	if rl.tokens >= 1 {
+		rl.tokens--
+		rl.mu.Unlock()
+		return nil
+	}
+
+	if rl.config.FailFast || rl.config.RequestsPerSecond <= 0 {
+		rl.mu.Unlock()
+		return NewRateLimitError(fmt.Sprintf("rate limit of %g requests per second exceeded", rl.config.RequestsPerSecond))
+	}
+
+	// Reserve the next token, and wait for it to be refilled
+	delay := time.Duration((1 - rl.tokens) / rl.config.RequestsPerSecond * float64(time.Second))
+	rl.tokens--
+	rl.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the reserved token back to the waiters behind us
+		rl.mu.Lock()
+		rl.tokens++
+		rl.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// A QuotaStore persists the request count of a DailyQuota, so the count survives restarts
+// and can be shared between processes.
+type QuotaStore interface {
+	// Increment atomically counts a request on the given day (formatted as "2006-01-02"), so that processes
+	// sharing the store never overwrite each other's counts.
+	//
+	// Parameters:
+	//   - ctx: The context for controlling cancellation and deadlines.
+	//   - day: The day to count the request on.
+	//
+	// Returns:
+	//   - int: The number of requests counted on the day, including this one.
+	//   - error: An error if the request cannot be counted.
+	Increment(ctx context.Context, day string) (int, error)
+}
+
+// A DailyQuota is a Limiter that admits a fixed number of requests per UTC day, such as OMDB's free tier.
+type DailyQuota struct {
+	limit int
+	store QuotaStore
+
+	mu    sync.Mutex
+	day   string
+	count int
+}
+
+// NewDailyQuota creates a new DailyQuota.
+//
+// Parameters:
+//   - limit: The number of requests admitted per day.
+//   - store: The QuotaStore used to persist the count, or nil to keep it in memory only.
+//
+// Returns:
+//   - *DailyQuota: A new instance of DailyQuota.
+func NewDailyQuota(limit int, store QuotaStore) *DailyQuota {
+	return &DailyQuota{
+		limit: limit,
+		store: store,
+	}
+}
+
+// Wait counts a request against today's quota.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//
+// Returns:
+//   - error: A RateLimitError if the quota is exhausted, or an error if the QuotaStore fails.
+func (dq *DailyQuota) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+
+	day := time.Now().UTC().Format(time.DateOnly)
+	if day != dq.day {
+		dq.day, dq.count = day, 0
+	}
+
+	// Counts only grow during a day, so an exhausted quota stays exhausted without asking the store
+	if dq.count >= dq.limit {
+		return NewQuotaExhaustedError(fmt.Sprintf("daily quota of %d requests exhausted", dq.limit))
+	}
+
+	count := dq.count + 1
+	if dq.store != nil {
+		// Only count the request once it's persisted, as a rejected request doesn't reach the provider
+		var err error
+		if count, err = dq.store.Increment(ctx, day); err != nil {
+			return err
+		}
+	}
+
+	// Other processes sharing the store may have used up the quota since the last request
+	dq.count = count
+	if count > dq.limit {
+		return NewQuotaExhaustedError(fmt.Sprintf("daily quota of %d requests exhausted", dq.limit))
+	}
+
+	return nil
+}
+
+// Remaining returns the number of requests left in today's quota.
+func (dq *DailyQuota) Remaining() int {
+	dq.mu.Lock()
+	defer dq.mu.Unlock()
+
+	if dq.day != time.Now().UTC().Format(time.DateOnly) {
+		return dq.limit
+	}
+
+	return max(0, dq.limit-dq.count)
+}
+
+// wait blocks until every configured Limiter admits a request. Daily quotas are waited on last, so that
+// a request refused by another Limiter doesn't use up a unit of a quota that only refills the next day.
+func (o providerOptions) wait(ctx context.Context) error {
+	var quotas []Limiter
+	for _, limiter := range o.limiters {
+		if _, ok := limiter.(*DailyQuota); ok {
+			quotas = append(quotas, limiter)
+			continue
+		}
+
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, quota := range quotas {
+		if err := quota.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,290 @@
+package search_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/jdahan/gogettitles/search"
+)
+
+// memoryQuotaStore is a QuotaStore backed by a map.
+type memoryQuotaStore struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newMemoryQuotaStore(counts map[string]int) *memoryQuotaStore {
+	return &memoryQuotaStore{counts: counts}
+}
+
+func (s *memoryQuotaStore) Increment(ctx context.Context, day string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counts[day]++
+	return s.counts[day], nil
+}
+
+func (s *memoryQuotaStore) count(day string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.counts[day]
+}
+
+func TestRateLimiter_Wait_Burst(t *testing.T) {
+	limiter := search.NewRateLimiter(search.RateLimiterConfig{RequestsPerSecond: 1, Burst: 3, FailFast: true})
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error on request %d: %v", i+1, err)
+		}
+	}
+
+	err := limiter.Wait(context.Background())
+	var rlErr *search.RateLimitError
+	if err == nil || !errors.As(err, &rlErr) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+}
+
+func TestRateLimiter_Wait_Blocks(t *testing.T) {
+	limiter := search.NewRateLimiter(search.RateLimiterConfig{RequestsPerSecond: 50})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected requests to be spaced out, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_Wait_ContextCancelled(t *testing.T) {
+	limiter := search.NewRateLimiter(search.RateLimiterConfig{RequestsPerSecond: 0.001})
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline exceeded error, got %v", err)
+	}
+}
+
+func TestDailyQuota_Wait(t *testing.T) {
+	store := newMemoryQuotaStore(map[string]int{time.Now().UTC().Format(time.DateOnly): 998})
+	quota := search.NewDailyQuota(1000, store)
+
+	if err := quota.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if remaining := quota.Remaining(); remaining != 1 {
+		t.Errorf("expected 1 remaining request, got %d", remaining)
+	}
+	if err := quota.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := quota.Wait(context.Background())
+	var rlErr *search.RateLimitError
+	if err == nil || !errors.As(err, &rlErr) || !rlErr.QuotaExhausted() {
+		t.Fatalf("expected quota exhausted error, got %v", err)
+	}
+	// The exhausted quota is known without counting the rejected request
+	if count := store.count(time.Now().UTC().Format(time.DateOnly)); count != 1000 {
+		t.Errorf("expected persisted count of 1000, got %d", count)
+	}
+}
+
+// failingQuotaStore is a QuotaStore whose Increment fails while failing is set.
+type failingQuotaStore struct {
+	*memoryQuotaStore
+	failing bool
+}
+
+func (s *failingQuotaStore) Increment(ctx context.Context, day string) (int, error) {
+	if s.failing {
+		return 0, errors.New("store unavailable")
+	}
+
+	return s.memoryQuotaStore.Increment(ctx, day)
+}
+
+func TestDailyQuota_Wait_StoreError(t *testing.T) {
+	store := &failingQuotaStore{memoryQuotaStore: newMemoryQuotaStore(map[string]int{}), failing: true}
+	quota := search.NewDailyQuota(2, store)
+
+	for range 3 {
+		if err := quota.Wait(context.Background()); err == nil {
+			t.Fatal("expected store error")
+		}
+	}
+
+	// Rejected requests aren't counted
+	if remaining := quota.Remaining(); remaining != 2 {
+		t.Errorf("expected 2 remaining requests, got %d", remaining)
+	}
+
+	store.failing = false
+	if err := quota.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count := store.count(time.Now().UTC().Format(time.DateOnly)); count != 1 {
+		t.Errorf("expected persisted count of 1, got %d", count)
+	}
+}
+
+func TestDailyQuota_Wait_SharedStore(t *testing.T) {
+	// Two processes sharing a store spend a single quota between them
+	store := newMemoryQuotaStore(map[string]int{})
+	quotas := []*search.DailyQuota{search.NewDailyQuota(10, store), search.NewDailyQuota(10, store)}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	admitted := 0
+	for _, quota := range quotas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for range 10 {
+				if quota.Wait(context.Background()) == nil {
+					mu.Lock()
+					admitted++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if admitted != 10 {
+		t.Errorf("expected 10 admitted requests, got %d", admitted)
+	}
+
+	for _, quota := range quotas {
+		if remaining := quota.Remaining(); remaining != 0 {
+			t.Errorf("expected the shared quota to be exhausted, got %d remaining", remaining)
+		}
+	}
+}
+
+func TestOmdbSearcher_Search_SharedLimiter(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	mockData, err := loadMockResponse("omdb_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("s", "Test").
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	quota := search.NewDailyQuota(1, nil)
+	first := search.NewOmdbSearcher(testAPIKey, http.DefaultClient, search.WithLimiter(quota))
+	second := search.NewOmdbSearcher(testAPIKey, http.DefaultClient, search.WithLimiter(quota))
+
+	if _, err := first.Search(context.Background(), "Test", 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = second.Search(context.Background(), "Test", 5)
+	var rlErr *search.RateLimitError
+	if err == nil || !errors.As(err, &rlErr) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+}
+
+func TestOmdbSearcher_Search_RequestLimitReached(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	serverResponse := `{
+		"Response":"False",
+		"Error":"Request limit reached!"
+	}`
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("s", "Test").
+		Reply(401).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient)
+	_, err := searcher.Search(context.Background(), "Test", 5)
+	var rlErr *search.RateLimitError
+	if err == nil || !errors.As(err, &rlErr) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+}
+
+func TestTmdbSearcher_Search_TooManyRequests(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	serverResponse := `{
+		"status_code":25,
+		"status_message":"Your request count is over the allowed limit.",
+		"success":false
+	}`
+
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/multi").
+		Get("/").
+		MatchParam("query", "Test").
+		Reply(429).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	_, err := searcher.Search(context.Background(), "Test", 5)
+	var rlErr *search.RateLimitError
+	if err == nil || !errors.As(err, &rlErr) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+}
+
+func TestOmdbSearcher_Search_QuotaCheckedLast(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	mockData, err := loadMockResponse("omdb_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("s", "Test").
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	quota := search.NewDailyQuota(10, nil)
+	limiter := search.NewRateLimiter(search.RateLimiterConfig{RequestsPerSecond: 0.001, FailFast: true})
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient, search.WithLimiter(quota), search.WithLimiter(limiter))
+
+	if _, err := searcher.Search(context.Background(), "Test", 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = searcher.Search(context.Background(), "Test", 5)
+	var rlErr *search.RateLimitError
+	if err == nil || !errors.As(err, &rlErr) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+
+	// The request refused by the rate limiter never reached OMDB, so it isn't counted
+	if remaining := quota.Remaining(); remaining != 9 {
+		t.Errorf("expected 9 remaining requests, got %d", remaining)
+	}
+}
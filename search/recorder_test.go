@@ -0,0 +1,92 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jdahan/gogettitles/search"
+	"github.com/jdahan/gogettitles/search/searchtest"
+)
+
+// newCassetteRecorder creates a Recorder replaying a cassette, or recording it against the real provider
+// when the GOGETTITLES_RECORD environment variable is set. Recording reads the provider's API key from
+// keyEnv and skips the test if it isn't set. Replaying skips the test if the cassette hasn't been recorded,
+// since cassettes only ever hold real provider responses.
+//
+// Returns:
+//   - *searchtest.Recorder: The Recorder to send requests through.
+//   - string: The API key to search with.
+func newCassetteRecorder(t *testing.T, name string, keyEnv string) (*searchtest.Recorder, string) {
+	t.Helper()
+
+	mode := searchtest.ModeFromEnv()
+
+	apiKey := testAPIKey
+	if mode == searchtest.ModeRecord {
+		if apiKey = os.Getenv(keyEnv); apiKey == "" {
+			t.Skipf("%s is required to record %s", keyEnv, name)
+		}
+	}
+
+	recorder, err := searchtest.NewRecorder("testdata/cassettes/"+name, searchtest.RecorderConfig{
+		Mode:    mode,
+		Secrets: []string{apiKey},
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skipf("%s hasn't been recorded; record it with %s=1 and %s set", name, searchtest.RecordEnv, keyEnv)
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := recorder.Save(); err != nil {
+			t.Errorf("failed to save cassette: %v", err)
+		}
+	})
+
+	return recorder, apiKey
+}
+
+func TestTmdbSearcher_Search_Cassette(t *testing.T) {
+	recorder, apiKey := newCassetteRecorder(t, "tmdb_search.json", "TMDB_API_KEY")
+	searcher := search.NewTmdbSearcher(apiKey, recorder.Client())
+
+	results, err := searcher.Search(context.Background(), "Star Wars", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+
+	if results[0].Title != "Star Wars" || results[0].Year != "1977" || results[0].Type != search.Movie || results[0].TmdbID != "11" {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+}
+
+func TestOmdbSearcher_Search_Cassette(t *testing.T) {
+	recorder, apiKey := newCassetteRecorder(t, "omdb_search.json", "OMDB_API_KEY")
+	searcher := search.NewOmdbSearcher(apiKey, recorder.Client())
+
+	results, err := searcher.Search(context.Background(), "Test", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+
+	// Only check what a fresh recording keeps stable, so the cassette can be refreshed without editing the test
+	for _, result := range results {
+		if result.Title == "" || !strings.HasPrefix(result.ImdbID, "tt") {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Package redisstore implements a search.CacheStore backed by Redis, or any server speaking the Redis protocol,
+// so several replicas of a service can share their cached searches.
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jdahan/gogettitles/search"
+	"github.com/redis/go-redis/v9"
+)
+
+// Config holds the configuration of a Store.
+type Config struct {
+	// Prefix is prepended to every key, to namespace the cache within a shared server. Defaults to "gogettitles:".
+	Prefix string
+	// Timeout bounds every operation, so a slow server is treated as a cache miss rather than delaying searches.
+	// It is enforced even if the client was not created with ContextTimeoutEnabled. Defaults to 100 milliseconds.
+	Timeout time.Duration
+}
+
+// A Store is a search.CacheStore that keeps entries in Redis, expiring them when the entries expire.
+type Store struct {
+	client redis.UniversalClient
+	config Config
+}
+
+// New creates a new Store that uses the specified Redis client.
+//
+// Parameters:
+//   - client: The Redis client to use, such as a *redis.Client or *redis.ClusterClient.
+//   - config: The configuration of the Store.
+//
+// Returns:
+//   - *Store: A new instance of Store.
+func New(client redis.UniversalClient, config Config) *Store {
+	if config.Prefix == "" {
+		config.Prefix = "gogettitles:"
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = 100 * time.Millisecond
+	}
+
+	return &Store{
+		client: client,
+		config: config,
+	}
+}
+
+// Get returns the entry cached under key, or nil if there is none or it was written with another schema version.
+func (s *Store) Get(ctx context.Context, key string) (*search.CacheEntry, error) {
+	var data []byte
+	err := s.do(ctx, func(ctx context.Context) (err error) {
+		data, err = s.client.Get(ctx, s.config.Prefix+key).Bytes()
+		return err
+	})
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return search.UnmarshalCacheEntry(data)
+}
+
+// Set caches the entry under key, letting Redis expire it when the entry expires.
+func (s *Store) Set(ctx context.Context, key string, entry *search.CacheEntry) error {
+	ttl := time.Until(entry.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	data, err := search.MarshalCacheEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	return s.do(ctx, func(ctx context.Context) error {
+		return s.client.Set(ctx, s.config.Prefix+key, data, ttl).Err()
+	})
+}
+
+// do runs a Redis operation, giving up once the timeout has elapsed.
+// The operation is left to finish in the background, bounded by the client's own timeouts.
+func (s *Store) do(ctx context.Context, op func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+
+	done := make(chan error, 1)
+	go func() {
+		defer cancel()
+		done <- op(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
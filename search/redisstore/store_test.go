@@ -0,0 +1,161 @@
+package redisstore_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jdahan/gogettitles/search"
+	"github.com/jdahan/gogettitles/search/redisstore"
+	"github.com/redis/go-redis/v9"
+)
+
+// countingSearcher returns a single result titled after the query, and counts its calls.
+type countingSearcher struct {
+	calls atomic.Int32
+}
+
+func (s *countingSearcher) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	s.calls.Add(1)
+	return []search.SearchResult{{Title: query, Type: search.Series}}, nil
+}
+
+func newTestStore(t *testing.T, config redisstore.Config) (*miniredis.Miniredis, *redisstore.Store) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return server, redisstore.New(client, config)
+}
+
+func TestStore_SetGet(t *testing.T) {
+	ctx := context.Background()
+	server, store := newTestStore(t, redisstore.Config{Prefix: "test:"})
+
+	entry := &search.CacheEntry{
+		Results:    []search.SearchResult{{Title: "Breaking Bad", Year: "2008", Type: search.Series}},
+		FreshUntil: time.Now().Add(time.Minute),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	if err := store.Set(ctx, "breaking bad", entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !server.Exists("test:breaking bad") {
+		t.Error("expected key to be prefixed")
+	}
+	if ttl := server.TTL("test:breaking bad"); ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Errorf("expected TTL of about an hour, got %v", ttl)
+	}
+
+	got, err := store.Get(ctx, "breaking bad")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || len(got.Results) != 1 || got.Results[0] != entry.Results[0] {
+		t.Errorf("expected cached entry, got %+v", got)
+	}
+}
+
+func TestStore_Get_Miss(t *testing.T) {
+	_, store := newTestStore(t, redisstore.Config{})
+
+	entry, err := store.Get(context.Background(), "unknown")
+	if err != nil || entry != nil {
+		t.Errorf("expected miss, got %+v, %v", entry, err)
+	}
+}
+
+func TestStore_Get_Expired(t *testing.T) {
+	ctx := context.Background()
+	server, store := newTestStore(t, redisstore.Config{})
+
+	entry := &search.CacheEntry{
+		Results:    []search.SearchResult{{Title: "Heat"}},
+		FreshUntil: time.Now().Add(time.Minute),
+		ExpiresAt:  time.Now().Add(time.Minute),
+	}
+	_ = store.Set(ctx, "heat", entry)
+	server.FastForward(2 * time.Minute)
+
+	if got, _ := store.Get(ctx, "heat"); got != nil {
+		t.Errorf("expected expired entry to be a miss, got %+v", got)
+	}
+}
+
+func TestStore_Get_SchemaVersionMismatch(t *testing.T) {
+	server, store := newTestStore(t, redisstore.Config{})
+
+	_ = server.Set("gogettitles:heat", `{"version":0,"results":[{"Title":"Heat"}]}`)
+
+	entry, err := store.Get(context.Background(), "heat")
+	if err != nil || entry != nil {
+		t.Errorf("expected entry from an old schema to be a miss, got %+v, %v", entry, err)
+	}
+}
+
+func TestCachingSearcher_SharedAcrossReplicas(t *testing.T) {
+	server := miniredis.RunT(t)
+	searcher := &countingSearcher{}
+
+	for i := 0; i < 3; i++ {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		defer client.Close()
+
+		replica := search.NewCachingSearcher(searcher, search.CachingSearcherConfig{
+			Store: redisstore.New(client, redisstore.Config{}),
+			TTL:   time.Minute,
+		})
+
+		results, err := replica.Search(context.Background(), "Breaking Bad", 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 1 || results[0].Type != search.Series {
+			t.Errorf("unexpected results: %+v", results)
+		}
+	}
+
+	if calls := searcher.calls.Load(); calls != 1 {
+		t.Errorf("expected replicas to share the cache, got %d upstream calls", calls)
+	}
+}
+
+func TestCachingSearcher_FallsBackWhenCacheIsSlow(t *testing.T) {
+	// A server that accepts connections but never replies
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String(), MaxRetries: -1})
+	defer client.Close()
+
+	searcher := &countingSearcher{}
+	cache := search.NewCachingSearcher(searcher, search.CachingSearcherConfig{
+		Store: redisstore.New(client, redisstore.Config{Timeout: 20 * time.Millisecond}),
+	})
+
+	start := time.Now()
+	results, err := cache.Search(context.Background(), "Heat", 5)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("expected provider results, got %+v, %v", results, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected cache operations to be bounded by the timeout, took %v", elapsed)
+	}
+}
@@ -0,0 +1,267 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Hints describe what is already known about the title being resolved.
+// They are used as search filters where possible, and to score the candidates.
+type Hints struct {
+	// Year, if set, is the expected release year (e.g. "1995"). Candidates from nearby years score lower.
+	Year string
+	// Type, if set, is the expected type of the title. Candidates of another type score lower.
+	Type ResultType
+}
+
+// A Candidate is a search result scored against a query.
+type Candidate struct {
+	// Result is the search result.
+	Result SearchResult
+	// Confidence is how well the result matches the query and hints, from 0 (no match) to 1 (exact match).
+	Confidence float64
+}
+
+// A Resolution is the single title a query resolved to.
+type Resolution struct {
+	// Result is the best matching search result.
+	Result SearchResult
+	// Confidence is how well the result matches the query and hints, from 0 (no match) to 1 (exact match).
+	Confidence float64
+	// RunnersUp are the next best candidates, best first.
+	RunnersUp []Candidate
+}
+
+// ResolverConfig holds the configuration of a Resolver.
+type ResolverConfig struct {
+	// Candidates is the number of search results scored for each query. Defaults to 10.
+	Candidates int
+	// Margin is the minimum lead the best candidate must have over the next one to be considered unambiguous.
+	// Defaults to 0.05; a negative margin disables ambiguity detection.
+	Margin float64
+	// RunnersUp is the maximum number of runner-ups returned with a Resolution. Defaults to 4.
+	RunnersUp int
+}
+
+// A Resolver resolves a query to a single title, for callers that need one answer rather than a list of results.
+type Resolver struct {
+	searcher Searcher
+	config   ResolverConfig
+}
+
+// NewResolver creates a new Resolver.
+//
+// Parameters:
+//   - searcher: The Searcher to retrieve candidates from.
+//   - config: The configuration of the Resolver.
+//
+// Returns:
+//   - *Resolver: A new instance of Resolver.
+func NewResolver(searcher Searcher, config ResolverConfig) *Resolver {
+	if config.Candidates <= 0 {
+		config.Candidates = 10
+	}
+
+	if config.Margin == 0 {
+		config.Margin = 0.05
+	}
+
+	if config.RunnersUp <= 0 {
+		config.RunnersUp = 4
+	}
+
+	return &Resolver{
+		searcher: searcher,
+		config:   config,
+	}
+}
+
+// Resolve searches for a query and returns the candidate that best matches it, scored by title similarity,
+// year proximity, and type agreement. The hints are first used to filter the search; if nothing matches,
+// the search is retried without them, since hints (such as the year in a filename) are often slightly off.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - query: The title to resolve.
+//   - hints: What is already known about the title.
+//
+// Returns:
+//   - *Resolution: The best matching result, its confidence, and the runner-ups.
+//   - error: A NoMatchError if nothing matches, an AmbiguousMatchError if the best candidates are too close to call,
+//     or an error if the search operation fails.
+func (r *Resolver) Resolve(ctx context.Context, query string, hints Hints) (*Resolution, error) {
+	opts := SearchOptionsFromContext(ctx)
+	filtered := opts
+	if filtered.Year == "" {
+		filtered.Year = hints.Year
+	}
+
+	if filtered.Type == "" {
+		filtered.Type = hints.Type
+	}
+
+	results, err := r.searcher.Search(WithSearchOptions(ctx, filtered), query, r.config.Candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 && filtered != opts {
+		if results, err = r.searcher.Search(ctx, query, r.config.Candidates); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, NewNoMatchError(query)
+	}
+
+	candidates := make([]Candidate, 0, len(results))
+	for _, result := range results {
+		candidates = append(candidates, Candidate{Result: result, Confidence: score(query, hints, result)})
+	}
+
+	// Stable, so ties keep the provider's relevance order
+	slices.SortStableFunc(candidates, func(a, b Candidate) int {
+		switch {
+		case a.Confidence > b.Confidence:
+			return -1
+		case a.Confidence < b.Confidence:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	best, rest := candidates[0], candidates[1:]
+
+	tied := 1
+	for tied < len(candidates) && best.Confidence-candidates[tied].Confidence < r.config.Margin {
+		tied++
+	}
+
+	if tied > 1 {
+		return nil, NewAmbiguousMatchError(query, candidates[:tied])
+	}
+
+	return &Resolution{
+		Result:     best.Result,
+		Confidence: best.Confidence,
+		RunnersUp:  rest[:min(r.config.RunnersUp, len(rest))],
+	}, nil
+}
+
+// score rates how well a search result matches a query and hints, from 0 to 1.
+// Title similarity dominates, with the year and type breaking ties between similarly named titles.
+// Hints that aren't set count as agreeing, so an exact title match without hints scores 1.
+func score(query string, hints Hints, result SearchResult) float64 {
+	title := similarity(query, result.Title)
+	if result.OriginalTitle != "" {
+		title = max(title, similarity(query, result.OriginalTitle))
+	}
+
+	year := 1.0
+	if hints.Year != "" {
+		year = 0
+		want, _ := strconv.Atoi(hints.Year)
+		if got, err := strconv.Atoi(result.StartYear()); err == nil {
+			year = max(0, 1-float64(max(want-got, got-want))/3)
+		}
+	}
+
+	kind := 1.0
+	if hints.Type != "" && hints.Type != result.Type {
+		kind = 0
+	}
+
+	return (7*title + 2*year + kind) / 10
+}
+
+// similarity returns the similarity of two titles from 0 to 1, ignoring case, punctuation, and spacing.
+func similarity(a, b string) float64 {
+	a, b = normalizeTitle(a), normalizeTitle(b)
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+
+	return 1 - float64(levenshtein(ra, rb))/float64(max(len(ra), len(rb)))
+}
+
+// normalizeTitle lowercases a title and strips everything but letters and digits.
+func normalizeTitle(title string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, title)
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b []rune) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		prev := row[0]
+		row[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			current := row[j]
+			if a[i-1] == b[j-1] {
+				row[j] = prev
+			} else {
+				row[j] = 1 + min(prev, row[j], row[j-1])
+			}
+
+			prev = current
+		}
+	}
+
+	return row[len(b)]
+}
+
+// NoMatchError is an error type that is returned when no search result matches a query being resolved.
+type NoMatchError struct {
+	query string
+}
+
+// NewNoMatchError creates a new NoMatchError for the specified query.
+func NewNoMatchError(query string) *NoMatchError {
+	return &NoMatchError{query: query}
+}
+
+// Error returns the error message associated with the NoMatchError.
+func (e *NoMatchError) Error() string {
+	return fmt.Sprintf("no title matches %q", e.query)
+}
+
+// AmbiguousMatchError is an error type that is returned when the best candidates for a query are too close to call.
+type AmbiguousMatchError struct {
+	query string
+	// Candidates are the candidates that tied for the best match, best first.
+	Candidates []Candidate
+}
+
+// NewAmbiguousMatchError creates a new AmbiguousMatchError for the specified query and tied candidates.
+func NewAmbiguousMatchError(query string, candidates []Candidate) *AmbiguousMatchError {
+	return &AmbiguousMatchError{query: query, Candidates: candidates}
+}
+
+// Error returns the error message associated with the AmbiguousMatchError.
+func (e *AmbiguousMatchError) Error() string {
+	titles := make([]string, len(e.Candidates))
+	for i, candidate := range e.Candidates {
+		titles[i] = fmt.Sprintf("%s (%s)", candidate.Result.Title, candidate.Result.Year)
+	}
+
+	return fmt.Sprintf("%q is ambiguous between %s", e.query, strings.Join(titles, ", "))
+}
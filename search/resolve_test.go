@@ -0,0 +1,137 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	searcher := &stubSearcher{results: []search.SearchResult{
+		{Title: "Heat", Year: "1986", Type: search.Movie, ImdbID: "tt0091209"},
+		{Title: "Heat", Year: "1995", Type: search.Movie, ImdbID: "tt0113277"},
+		{Title: "The Heat", Year: "2013", Type: search.Movie, ImdbID: "tt2404463"},
+	}}
+	resolver := search.NewResolver(searcher, search.ResolverConfig{})
+
+	resolution, err := resolver.Resolve(context.Background(), "Heat", search.Hints{Year: "1995", Type: search.Movie})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resolution.Result.ImdbID != "tt0113277" || resolution.Confidence != 1 {
+		t.Errorf("expected an exact match on Heat (1995), got %+v", resolution)
+	}
+	if len(resolution.RunnersUp) != 2 || resolution.RunnersUp[0].Result.ImdbID != "tt0091209" {
+		t.Errorf("expected runner-ups ordered by confidence, got %+v", resolution.RunnersUp)
+	}
+	if searcher.opts.Year != "1995" || searcher.opts.Type != search.Movie {
+		t.Errorf("expected hints to be used as search filters, got %+v", searcher.opts)
+	}
+}
+
+func TestResolver_Resolve_YearProximity(t *testing.T) {
+	searcher := &stubSearcher{results: []search.SearchResult{
+		{Title: "Heat", Year: "1972", Type: search.Movie},
+		{Title: "Heat", Year: "1995", Type: search.Movie},
+	}}
+	resolver := search.NewResolver(searcher, search.ResolverConfig{})
+
+	resolution, err := resolver.Resolve(context.Background(), "Heat", search.Hints{Year: "1996"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resolution.Result.Year != "1995" || resolution.Confidence >= 1 {
+		t.Errorf("expected the closest year with reduced confidence, got %+v", resolution)
+	}
+}
+
+func TestResolver_Resolve_SeriesYearRange(t *testing.T) {
+	// OMDB reports the run of a series, which still matches on its start year
+	searcher := &stubSearcher{results: []search.SearchResult{
+		{Title: "Doctor Who", Year: "1963–1989", Type: search.Series, ImdbID: "tt0056751"},
+		{Title: "Doctor Who", Year: "2005–", Type: search.Series, ImdbID: "tt0436992"},
+		{Title: "Doctor Who Confidential", Year: "2005–2011", Type: search.Series, ImdbID: "tt0461097"},
+	}}
+	resolver := search.NewResolver(searcher, search.ResolverConfig{})
+
+	resolution, err := resolver.Resolve(context.Background(), "Doctor Who", search.Hints{Year: "2005", Type: search.Series})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resolution.Result.ImdbID != "tt0436992" || resolution.Confidence != 1 {
+		t.Errorf("expected an exact match on Doctor Who (2005–), got %+v", resolution)
+	}
+}
+
+func TestResolver_Resolve_TypeAgreement(t *testing.T) {
+	searcher := &stubSearcher{results: []search.SearchResult{
+		{Title: "Fargo", Year: "1996", Type: search.Movie},
+		{Title: "Fargo", Year: "2014", Type: search.Series},
+	}}
+	resolver := search.NewResolver(searcher, search.ResolverConfig{})
+
+	resolution, err := resolver.Resolve(context.Background(), "fargo", search.Hints{Type: search.Series})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resolution.Result.Type != search.Series {
+		t.Errorf("expected the series, got %+v", resolution.Result)
+	}
+}
+
+func TestResolver_Resolve_Ambiguous(t *testing.T) {
+	searcher := &stubSearcher{results: []search.SearchResult{
+		{Title: "Heat", Year: "1986", Type: search.Movie},
+		{Title: "Heat", Year: "1995", Type: search.Movie},
+		{Title: "The Heat", Year: "2013", Type: search.Movie},
+	}}
+	resolver := search.NewResolver(searcher, search.ResolverConfig{})
+
+	_, err := resolver.Resolve(context.Background(), "Heat", search.Hints{})
+
+	var ambiguous *search.AmbiguousMatchError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("expected AmbiguousMatchError, got %v", err)
+	}
+	if len(ambiguous.Candidates) != 2 {
+		t.Errorf("expected the two tied candidates, got %+v", ambiguous.Candidates)
+	}
+}
+
+func TestResolver_Resolve_AmbiguityDisabled(t *testing.T) {
+	searcher := &stubSearcher{results: []search.SearchResult{
+		{Title: "Heat", Year: "1986", Type: search.Movie},
+		{Title: "Heat", Year: "1995", Type: search.Movie},
+	}}
+	resolver := search.NewResolver(searcher, search.ResolverConfig{Margin: -1})
+
+	resolution, err := resolver.Resolve(context.Background(), "Heat", search.Hints{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resolution.Result.Year != "1986" {
+		t.Errorf("expected ties to keep the provider's order, got %+v", resolution.Result)
+	}
+}
+
+func TestResolver_Resolve_NoMatch(t *testing.T) {
+	searcher := &stubSearcher{}
+	resolver := search.NewResolver(searcher, search.ResolverConfig{})
+
+	_, err := resolver.Resolve(context.Background(), "Heat", search.Hints{Year: "1995"})
+
+	var noMatch *search.NoMatchError
+	if !errors.As(err, &noMatch) {
+		t.Errorf("expected NoMatchError, got %v", err)
+	}
+	if searcher.calls != 2 {
+		t.Errorf("expected a retry without hints, got %d calls", searcher.calls)
+	}
+}
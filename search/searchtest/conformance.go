@@ -0,0 +1,205 @@
+// Package searchtest provides utilities for testing search.Searcher implementations,
+// including a conformance suite that checks a Searcher behaves like the built-in ones.
+package searchtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// A Fixture describes what the provider behind a Searcher under test must serve.
+type Fixture struct {
+	// Results are the results the provider holds for any query, in the order it ranks them.
+	Results []search.SearchResult
+	// PageSize is the number of results the provider must return per page.
+	PageSize int
+	// Fail makes the provider fail every request, as its API would on an internal error.
+	Fail bool
+	// OnPage, if set, must be called by the provider with the 1-based page number before serving each page.
+	OnPage func(page int)
+}
+
+// A Factory creates the Searcher under test, backed by a provider (typically a fake HTTP server)
+// serving the fixture in the provider's own format.
+type Factory func(t *testing.T, fixture Fixture) search.Searcher
+
+// conformancePageSize is the page size used by the conformance suite.
+const conformancePageSize = 10
+
+// RunConformance runs the conformance suite against the Searcher created by factory. It checks that:
+//   - maxResults <= 0 fails with a search.InvalidMaxResultsError,
+//   - exactly maxResults results are returned (or all of them, if fewer), in order, across pages,
+//     without requesting more pages than needed,
+//   - cancelling the context mid-pagination fails the search and stops pagination,
+//   - a query without results returns no results and no error, whereas a provider failure returns an error,
+//   - result types and fields are mapped from the provider's format.
+//
+// Parameters:
+//   - t: The test to run the suite in.
+//   - factory: The factory creating the Searcher under test for each case.
+func RunConformance(t *testing.T, factory Factory) {
+	t.Helper()
+
+	t.Run("InvalidMaxResults", func(t *testing.T) {
+		searcher := factory(t, Fixture{Results: catalogue(5), PageSize: conformancePageSize})
+
+		for _, maxResults := range []int{0, -1} {
+			results, err := searcher.Search(context.Background(), "Title", maxResults)
+
+			var mrErr *search.InvalidMaxResultsError
+			if !errors.As(err, &mrErr) {
+				t.Errorf("maxResults %d: expected InvalidMaxResultsError, got %v", maxResults, err)
+			}
+			if results != nil {
+				t.Errorf("maxResults %d: expected no results, got %+v", maxResults, results)
+			}
+		}
+	})
+
+	t.Run("ExactMaxResults", func(t *testing.T) {
+		const total = 25
+
+		for _, maxResults := range []int{1, 10, 11, 20, 25, 40} {
+			t.Run(fmt.Sprint(maxResults), func(t *testing.T) {
+				var pages pageRecorder
+				fixture := Fixture{Results: catalogue(total), PageSize: conformancePageSize, OnPage: pages.record}
+
+				results, err := factory(t, fixture).Search(context.Background(), "Title", maxResults)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				want := min(maxResults, total)
+				if len(results) != want {
+					t.Fatalf("expected %d results, got %d", want, len(results))
+				}
+				for i, result := range results {
+					if result.Title != fixture.Results[i].Title {
+						t.Errorf("result %d: expected %q, got %q", i, fixture.Results[i].Title, result.Title)
+					}
+				}
+
+				if needed := (want + conformancePageSize - 1) / conformancePageSize; pages.max() > needed {
+					t.Errorf("expected at most %d pages to be requested, got page %d", needed, pages.max())
+				}
+			})
+		}
+	})
+
+	t.Run("CancellationMidPagination", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var pages pageRecorder
+		fixture := Fixture{Results: catalogue(50), PageSize: conformancePageSize, OnPage: func(page int) {
+			pages.record(page)
+			if page == 2 {
+				cancel()
+			}
+		}}
+
+		results, err := factory(t, fixture).Search(ctx, "Title", 50)
+		if err == nil {
+			t.Fatalf("expected an error after cancellation, got %d results", len(results))
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected a context cancellation error, got %v", err)
+		}
+		if results != nil {
+			t.Errorf("expected no results after cancellation, got %d", len(results))
+		}
+		if pages.max() > 2 {
+			t.Errorf("expected pagination to stop after cancellation, got page %d", pages.max())
+		}
+	})
+
+	t.Run("EmptyResults", func(t *testing.T) {
+		results, err := factory(t, Fixture{PageSize: conformancePageSize}).Search(context.Background(), "Nothing", 5)
+		if err != nil {
+			t.Fatalf("expected no error for a query without results, got %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("expected no results, got %+v", results)
+		}
+	})
+
+	t.Run("ProviderError", func(t *testing.T) {
+		results, err := factory(t, Fixture{Results: catalogue(5), PageSize: conformancePageSize, Fail: true}).
+			Search(context.Background(), "Title", 5)
+		if err == nil {
+			t.Fatalf("expected an error when the provider fails, got %+v", results)
+		}
+		if results != nil {
+			t.Errorf("expected no results when the provider fails, got %+v", results)
+		}
+	})
+
+	t.Run("TypeMapping", func(t *testing.T) {
+		fixture := Fixture{
+			Results: []search.SearchResult{
+				{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093", Type: search.Movie},
+				{Title: "Breaking Bad", Year: "2008", ImdbID: "tt0903747", Type: search.Series},
+				{Title: "Heat", Year: "1995", ImdbID: "tt0113277", Type: search.Movie},
+			},
+			PageSize: conformancePageSize,
+		}
+
+		results, err := factory(t, fixture).Search(context.Background(), "Title", 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != len(fixture.Results) {
+			t.Fatalf("expected %d results, got %d", len(fixture.Results), len(results))
+		}
+
+		for i, result := range results {
+			want := fixture.Results[i]
+			if result.Title != want.Title || result.Year != want.Year || result.Type != want.Type {
+				t.Errorf("result %d: expected %s (%s) of type %q, got %s (%s) of type %q",
+					i, want.Title, want.Year, want.Type, result.Title, result.Year, result.Type)
+			}
+		}
+	})
+}
+
+// catalogue returns n distinct movies.
+func catalogue(n int) []search.SearchResult {
+	results := make([]search.SearchResult, n)
+	for i := range results {
+		results[i] = search.SearchResult{
+			Title:  fmt.Sprintf("Title %d", i+1),
+			Year:   fmt.Sprint(1950 + i),
+			ImdbID: fmt.Sprintf("tt%07d", i+1),
+			Type:   search.Movie,
+		}
+	}
+
+	return results
+}
+
+// A pageRecorder records the pages a provider serves.
+type pageRecorder struct {
+	mu      sync.Mutex
+	highest int
+}
+
+// record records that a page was served.
+func (pr *pageRecorder) record(page int) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pr.highest = max(pr.highest, page)
+}
+
+// max returns the highest page served.
+func (pr *pageRecorder) max() int {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	return pr.highest
+}
@@ -0,0 +1,291 @@
+package searchtest
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// A Call is a recorded call to a FakeSearcher.
+type Call struct {
+	// Query is the search query.
+	Query string
+	// MaxResults is the maximum number of results requested.
+	MaxResults int
+	// Options are the SearchOptions carried by the call's context.
+	Options search.SearchOptions
+}
+
+// A FakeSearcher is an in-memory search.Searcher backed by a catalogue of titles, for testing code that consumes
+// a Searcher without HTTP mocks. Titles match when each word of the query is a prefix of a word of the title,
+// as autocomplete providers do, and are ranked exact matches first, then titles starting with the query,
+// then in catalogue order. Latency and errors can be scripted, and calls are recorded.
+//
+// A FakeSearcher is safe for concurrent use.
+type FakeSearcher struct {
+	mu        sync.Mutex
+	catalogue []search.SearchResult
+	latency   time.Duration
+	err       error
+	scripted  []error
+	queryErrs map[string]error
+	calls     []Call
+	hook      func(Call)
+}
+
+// NewFakeSearcher creates a new FakeSearcher.
+//
+// Parameters:
+//   - catalogue: The titles to search, most popular first, or nil to use DefaultCatalogue.
+//
+// Returns:
+//   - *FakeSearcher: A new instance of FakeSearcher.
+func NewFakeSearcher(catalogue []search.SearchResult) *FakeSearcher {
+	if catalogue == nil {
+		catalogue = DefaultCatalogue()
+	}
+
+	return &FakeSearcher{
+		catalogue: slices.Clone(catalogue),
+		queryErrs: map[string]error{},
+	}
+}
+
+// SetLatency makes every subsequent search take the given time, or until its context is done.
+func (fs *FakeSearcher) SetLatency(latency time.Duration) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.latency = latency
+}
+
+// FailWith makes every subsequent search fail with err, until called again with nil.
+// Use the search package's constructors for realistic errors, e.g. search.NewRateLimitError.
+func (fs *FakeSearcher) FailWith(err error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.err = err
+}
+
+// FailNext scripts the outcome of the next searches: the nth next search fails with errs[n],
+// or proceeds normally if errs[n] is nil. Scripted outcomes take precedence over FailWith and FailQuery.
+func (fs *FakeSearcher) FailNext(errs ...error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.scripted = append(fs.scripted, errs...)
+}
+
+// FailQuery makes every subsequent search for query (ignoring case and surrounding spaces) fail with err,
+// or removes the failure if err is nil.
+func (fs *FakeSearcher) FailQuery(query string, err error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	key := strings.ToLower(strings.TrimSpace(query))
+	if err == nil {
+		delete(fs.queryErrs, key)
+	} else {
+		fs.queryErrs[key] = err
+	}
+}
+
+// OnSearch registers a hook called at the start of every search, e.g. to block or synchronize with a test.
+func (fs *FakeSearcher) OnSearch(hook func(Call)) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.hook = hook
+}
+
+// Calls returns the calls made so far, in order.
+func (fs *FakeSearcher) Calls() []Call {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return slices.Clone(fs.calls)
+}
+
+// Reset clears the recorded calls and any scripted latency and errors.
+func (fs *FakeSearcher) Reset() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.latency, fs.err, fs.scripted, fs.calls = 0, nil, nil, nil
+	fs.queryErrs = map[string]error{}
+}
+
+// Search searches the catalogue, after any scripted latency, unless an error is scripted.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - query: The search query string.
+//   - maxResults: The maximum number of search results to return.
+//
+// Returns:
+//   - []search.SearchResult: A slice containing the search results.
+//   - error: A search.InvalidMaxResultsError if maxResults <= 0, the scripted error, if any,
+//     or the context's error if it is done before the scripted latency elapses.
+func (fs *FakeSearcher) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	call := Call{Query: query, MaxResults: maxResults, Options: search.SearchOptionsFromContext(ctx)}
+
+	fs.mu.Lock()
+	fs.calls = append(fs.calls, call)
+	latency, hook := fs.latency, fs.hook
+
+	err := fs.err
+	if len(fs.scripted) > 0 {
+		err, fs.scripted = fs.scripted[0], fs.scripted[1:]
+	} else if queryErr, ok := fs.queryErrs[strings.ToLower(strings.TrimSpace(query))]; ok {
+		err = queryErr
+	}
+	fs.mu.Unlock()
+
+	if hook != nil {
+		hook(call)
+	}
+
+	if maxResults <= 0 {
+		return nil, search.NewInvalidMaxResultsError()
+	}
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	} else if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.match(query, maxResults, call.Options), nil
+}
+
+// match returns the titles in the catalogue matching a query.
+func (fs *FakeSearcher) match(query string, maxResults int, opts search.SearchOptions) []search.SearchResult {
+	words := fakeWords(query)
+	if len(words) == 0 {
+		return []search.SearchResult{}
+	}
+
+	normalized := strings.Join(words, " ")
+
+	type ranked struct {
+		result search.SearchResult
+		rank   int
+	}
+
+	// The catalogue is never modified, so it can be read without locking
+	var matches []ranked
+	for _, result := range fs.catalogue {
+		if opts.Year != "" && result.Year != opts.Year || opts.Type != "" && result.Type != opts.Type {
+			continue
+		}
+
+		if opts.SafeSearch && result.Adult {
+			continue
+		}
+
+		rank := -1
+		for _, title := range []string{result.Title, result.OriginalTitle} {
+			titleWords := fakeWords(title)
+			if !prefixesMatch(words, titleWords) {
+				continue
+			}
+
+			titleRank := 2
+			switch normalizedTitle := strings.Join(titleWords, " "); {
+			case normalizedTitle == normalized:
+				titleRank = 0
+			case strings.HasPrefix(normalizedTitle, normalized):
+				titleRank = 1
+			}
+
+			if rank < 0 || titleRank < rank {
+				rank = titleRank
+			}
+		}
+
+		if rank >= 0 {
+			matches = append(matches, ranked{result: result, rank: rank})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b ranked) int {
+		return a.rank - b.rank
+	})
+
+	results := make([]search.SearchResult, 0, min(maxResults, len(matches)))
+	for _, match := range matches[:min(maxResults, len(matches))] {
+		results = append(results, match.result)
+	}
+
+	return results
+}
+
+// fakeWords splits a title into lowercase words, ignoring punctuation.
+func fakeWords(title string) []string {
+	return strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// prefixesMatch reports whether each query word is a prefix of a distinct title word.
+func prefixesMatch(query, title []string) bool {
+	used := make([]bool, len(title))
+
+	for _, word := range query {
+		found := false
+		for i, titleWord := range title {
+			if !used[i] && strings.HasPrefix(titleWord, word) {
+				used[i], found = true, true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DefaultCatalogue returns a catalogue of well-known movies and series, most popular first.
+//
+// Returns:
+//   - []search.SearchResult: A new copy of the catalogue.
+func DefaultCatalogue() []search.SearchResult {
+	return []search.SearchResult{
+		{Title: "The Matrix", Year: "1999", ImdbID: "tt0133093", TmdbID: "603", Type: search.Movie},
+		{Title: "Breaking Bad", Year: "2008", ImdbID: "tt0903747", TmdbID: "1396", Type: search.Series},
+		{Title: "Star Wars", Year: "1977", ImdbID: "tt0076759", TmdbID: "11", Type: search.Movie},
+		{Title: "The Office", Year: "2005", ImdbID: "tt0386676", TmdbID: "2316", Type: search.Series},
+		{Title: "Heat", Year: "1995", ImdbID: "tt0113277", TmdbID: "949", Type: search.Movie},
+		{Title: "The Matrix Reloaded", Year: "2003", ImdbID: "tt0234215", TmdbID: "604", Type: search.Movie},
+		{Title: "Star Wars: The Empire Strikes Back", Year: "1980", ImdbID: "tt0080684", TmdbID: "1891", Type: search.Movie},
+		{Title: "Game of Thrones", Year: "2011", ImdbID: "tt0944947", TmdbID: "1399", Type: search.Series},
+		{Title: "The Matrix Revolutions", Year: "2003", ImdbID: "tt0242653", TmdbID: "605", Type: search.Movie},
+		{Title: "Spirited Away", Year: "2001", ImdbID: "tt0245429", TmdbID: "129", Type: search.Movie, OriginalTitle: "千と千尋の神隠し", OriginalLanguage: "ja"},
+		{Title: "Star Wars: Andor", Year: "2022", ImdbID: "tt9253284", TmdbID: "83867", Type: search.Series},
+		{Title: "Amélie", Year: "2001", ImdbID: "tt0211915", TmdbID: "194", Type: search.Movie, OriginalTitle: "Le Fabuleux Destin d'Amélie Poulain", OriginalLanguage: "fr"},
+		{Title: "Heat", Year: "1986", ImdbID: "tt0091209", TmdbID: "10729", Type: search.Movie},
+		{Title: "The Office", Year: "2001", ImdbID: "tt0290978", TmdbID: "2996", Type: search.Series},
+		{Title: "Fargo", Year: "1996", ImdbID: "tt0116282", TmdbID: "275", Type: search.Movie},
+		{Title: "Fargo", Year: "2014", ImdbID: "tt2802850", TmdbID: "60622", Type: search.Series},
+	}
+}
@@ -0,0 +1,169 @@
+package searchtest_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jdahan/gogettitles/search"
+	"github.com/jdahan/gogettitles/search/searchtest"
+)
+
+func titles(results []search.SearchResult) []string {
+	var titles []string
+	for _, result := range results {
+		titles = append(titles, result.Title+" ("+result.Year+")")
+	}
+	return titles
+}
+
+func TestFakeSearcher_Search_PrefixMatching(t *testing.T) {
+	searcher := searchtest.NewFakeSearcher(nil)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"matrix", []string{"The Matrix (1999)", "The Matrix Reloaded (2003)", "The Matrix Revolutions (2003)"}},
+		{"the mat rel", []string{"The Matrix Reloaded (2003)"}},
+		{"star wars", []string{"Star Wars (1977)", "Star Wars: The Empire Strikes Back (1980)", "Star Wars: Andor (2022)"}},
+		{"wars star", []string{"Star Wars (1977)", "Star Wars: The Empire Strikes Back (1980)", "Star Wars: Andor (2022)"}},
+		{"Heat", []string{"Heat (1995)", "Heat (1986)"}},
+		{"fabuleux destin", []string{"Amélie (2001)"}},
+		{"atrix", nil},
+		{"  ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := searcher.Search(context.Background(), tt.query, 10)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := titles(results); !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFakeSearcher_Search_Ranking(t *testing.T) {
+	searcher := searchtest.NewFakeSearcher([]search.SearchResult{
+		{Title: "Return of the Office", Type: search.Movie},
+		{Title: "The Office Christmas Party", Type: search.Movie},
+		{Title: "The Office", Type: search.Series},
+	})
+
+	results, err := searcher.Search(context.Background(), "the office", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"The Office ()", "The Office Christmas Party ()", "Return of the Office ()"}
+	if got := titles(results); !slices.Equal(got, want) {
+		t.Errorf("expected exact, then prefix, then word matches: %v, got %v", want, got)
+	}
+}
+
+func TestFakeSearcher_Search_MaxResultsAndOptions(t *testing.T) {
+	searcher := searchtest.NewFakeSearcher(nil)
+
+	if _, err := searcher.Search(context.Background(), "matrix", 0); !errors.As(err, new(*search.InvalidMaxResultsError)) {
+		t.Errorf("expected InvalidMaxResultsError, got %v", err)
+	}
+
+	results, _ := searcher.Search(context.Background(), "matrix", 2)
+	if len(results) != 2 {
+		t.Errorf("expected 2 results, got %d", len(results))
+	}
+
+	ctx := search.WithSearchOptions(context.Background(), search.SearchOptions{Type: search.Series})
+	results, _ = searcher.Search(ctx, "fargo", 10)
+	if got := titles(results); !slices.Equal(got, []string{"Fargo (2014)"}) {
+		t.Errorf("expected the Type option to filter results, got %v", got)
+	}
+
+	ctx = search.WithSearchOptions(context.Background(), search.SearchOptions{Year: "1986"})
+	results, _ = searcher.Search(ctx, "heat", 10)
+	if got := titles(results); !slices.Equal(got, []string{"Heat (1986)"}) {
+		t.Errorf("expected the Year option to filter results, got %v", got)
+	}
+}
+
+func TestFakeSearcher_Search_ScriptedErrors(t *testing.T) {
+	searcher := searchtest.NewFakeSearcher(nil)
+	rateLimitErr := search.NewRateLimitError("too many requests")
+	parsingErr := search.NewResultParsingError("unexpected end of JSON input")
+
+	searcher.FailNext(rateLimitErr, nil, parsingErr)
+	for i, want := range []error{rateLimitErr, nil, parsingErr, nil} {
+		if _, err := searcher.Search(context.Background(), "matrix", 5); err != want {
+			t.Errorf("call %d: expected %v, got %v", i, want, err)
+		}
+	}
+
+	providerErr := search.NewSearchProviderError("service unavailable")
+	searcher.FailQuery("Heat", providerErr)
+	if _, err := searcher.Search(context.Background(), " heat ", 5); err != providerErr {
+		t.Errorf("expected the query to fail with %v, got %v", providerErr, err)
+	}
+	if _, err := searcher.Search(context.Background(), "matrix", 5); err != nil {
+		t.Errorf("expected other queries to succeed, got %v", err)
+	}
+
+	searcher.FailWith(providerErr)
+	if _, err := searcher.Search(context.Background(), "matrix", 5); err != providerErr {
+		t.Errorf("expected every query to fail with %v, got %v", providerErr, err)
+	}
+
+	searcher.Reset()
+	if _, err := searcher.Search(context.Background(), "heat", 5); err != nil {
+		t.Errorf("expected Reset to clear scripted errors, got %v", err)
+	}
+}
+
+func TestFakeSearcher_Search_Latency(t *testing.T) {
+	searcher := searchtest.NewFakeSearcher(nil)
+	searcher.SetLatency(20 * time.Millisecond)
+
+	start := time.Now()
+	if _, err := searcher.Search(context.Background(), "matrix", 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected the search to take at least 20ms, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := searcher.Search(ctx, "matrix", 5); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestFakeSearcher_Calls(t *testing.T) {
+	searcher := searchtest.NewFakeSearcher(nil)
+
+	var hooked sync.WaitGroup
+	hooked.Add(2)
+	searcher.OnSearch(func(call searchtest.Call) { hooked.Done() })
+
+	ctx := search.WithSearchOptions(context.Background(), search.SearchOptions{Language: "de"})
+	_, _ = searcher.Search(ctx, "matrix", 5)
+	_, _ = searcher.Search(context.Background(), "heat", 1)
+	hooked.Wait()
+
+	calls := searcher.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+	if calls[0].Query != "matrix" || calls[0].MaxResults != 5 || calls[0].Options.Language != "de" {
+		t.Errorf("unexpected first call: %+v", calls[0])
+	}
+	if calls[1].Query != "heat" || calls[1].MaxResults != 1 {
+		t.Errorf("unexpected second call: %+v", calls[1])
+	}
+}
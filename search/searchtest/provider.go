@@ -0,0 +1,364 @@
+package searchtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// FakeProviderConfig holds the configuration of a FakeProvider.
+type FakeProviderConfig struct {
+	// Catalogue holds the titles served, most popular first. Defaults to DefaultCatalogue.
+	Catalogue []search.SearchResult
+	// TmdbAPIKey is the API key TMDB requests must present. If empty, any non-empty key is accepted.
+	TmdbAPIKey string
+	// OmdbAPIKey is the API key OMDB requests must present. If empty, any non-empty key is accepted.
+	OmdbAPIKey string
+	// TmdbPageSize is the number of results per TMDB page. Defaults to 20, as TMDB serves.
+	TmdbPageSize int
+	// OmdbPageSize is the number of results per OMDB page. Defaults to 10, as OMDB serves.
+	OmdbPageSize int
+}
+
+// A FakeProvider is an http.Handler emulating the TMDB search endpoints ("/3/search/multi", "/3/search/movie",
+// and "/3/search/tv"), the TMDB key validation endpoint ("/3/authentication"), and the OMDB search endpoint
+// ("/?s="), serving a catalogue with the same prefix matching as FakeSearcher.
+// Point searchers at it using search.WithBaseURL, e.g. with httptest.NewServer(NewFakeProvider(config)).
+//
+// Failures can be injected with Inject, or by other processes by POSTing to "/_fakeprovider/inject"
+// with the "status" and optional "count" form values.
+type FakeProvider struct {
+	config   FakeProviderConfig
+	searcher *FakeSearcher
+
+	mu       sync.Mutex
+	injected []int
+	requests int
+}
+
+// NewFakeProvider creates a new FakeProvider.
+//
+// Parameters:
+//   - config: The configuration of the FakeProvider.
+//
+// Returns:
+//   - *FakeProvider: A new instance of FakeProvider.
+func NewFakeProvider(config FakeProviderConfig) *FakeProvider {
+	if config.TmdbPageSize <= 0 {
+		config.TmdbPageSize = 20
+	}
+
+	if config.OmdbPageSize <= 0 {
+		config.OmdbPageSize = 10
+	}
+
+	return &FakeProvider{
+		config:   config,
+		searcher: NewFakeSearcher(config.Catalogue),
+	}
+}
+
+// Inject makes the next count search requests fail with the given HTTP status, such as
+// http.StatusTooManyRequests or http.StatusServiceUnavailable, with a body in the provider's error format.
+func (fp *FakeProvider) Inject(status int, count int) {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	for range count {
+		fp.injected = append(fp.injected, status)
+	}
+}
+
+// Requests returns the number of search requests served, including rejected and failed ones.
+func (fp *FakeProvider) Requests() int {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	return fp.requests
+}
+
+// ServeHTTP serves a TMDB or OMDB search request.
+func (fp *FakeProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	switch {
+	case r.URL.Path == "/_fakeprovider/inject" && r.Method == http.MethodPost:
+		fp.serveInject(w, r)
+	case r.URL.Path == "/3/search/multi" && r.Method == http.MethodGet:
+		fp.serveTmdb(w, r, "", "")
+	case r.URL.Path == "/3/search/movie" && r.Method == http.MethodGet:
+		fp.serveTmdb(w, r, search.Movie, r.URL.Query().Get("primary_release_year"))
+	case r.URL.Path == "/3/search/tv" && r.Method == http.MethodGet:
+		fp.serveTmdb(w, r, search.Series, r.URL.Query().Get("first_air_date_year"))
+	case r.URL.Path == "/3/authentication" && r.Method == http.MethodGet:
+		fp.serveTmdbAuthentication(w, r)
+	case r.URL.Path == "/" && r.Method == http.MethodGet:
+		fp.serveOmdb(w, r)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		writeJSON(w, map[string]any{"success": false, "status_code": 34, "status_message": "The resource you requested could not be found."})
+	}
+}
+
+// serveInject injects failures requested over HTTP.
+func (fp *FakeProvider) serveInject(w http.ResponseWriter, r *http.Request) {
+	status, err := strconv.Atoi(r.FormValue("status"))
+	if err != nil || status < 400 || status > 599 {
+		http.Error(w, "status must be an HTTP error status", http.StatusBadRequest)
+		return
+	}
+
+	count := 1
+	if value := r.FormValue("count"); value != "" {
+		if count, err = strconv.Atoi(value); err != nil || count < 1 {
+			http.Error(w, "count must be a positive integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	fp.Inject(status, count)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// nextInjected counts a search request and returns the status injected for it, or zero if none is.
+func (fp *FakeProvider) nextInjected() int {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	fp.requests++
+	if len(fp.injected) == 0 {
+		return 0
+	}
+
+	status := fp.injected[0]
+	fp.injected = fp.injected[1:]
+
+	return status
+}
+
+// validKey reports whether a presented API key is accepted.
+func validKey(presented, expected string) bool {
+	if expected == "" {
+		return presented != ""
+	}
+
+	return presented == expected
+}
+
+// serveTmdbAuthentication serves a TMDB key validation request.
+func (fp *FakeProvider) serveTmdbAuthentication(w http.ResponseWriter, r *http.Request) {
+	if !fp.admitTmdb(w, r) {
+		return
+	}
+
+	writeJSON(w, map[string]any{"success": true, "status_code": 1, "status_message": "Success."})
+}
+
+// admitTmdb serves any injected failure or authentication error for a TMDB request, and reports whether
+// the request should be served normally.
+func (fp *FakeProvider) admitTmdb(w http.ResponseWriter, r *http.Request) bool {
+	if status := fp.nextInjected(); status != 0 {
+		w.WriteHeader(status)
+		if status == http.StatusTooManyRequests {
+			writeJSON(w, map[string]any{"success": false, "status_code": 25, "status_message": "Your request count is over the allowed limit."})
+		} else {
+			writeJSON(w, map[string]any{"success": false, "status_code": 11, "status_message": "Internal error: Something went wrong, contact TMDb."})
+		}
+		return false
+	}
+
+	key := r.URL.Query().Get("api_key")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		key = bearer
+	}
+
+	if !validKey(key, fp.config.TmdbAPIKey) {
+		w.WriteHeader(http.StatusUnauthorized)
+		writeJSON(w, map[string]any{"success": false, "status_code": 7, "status_message": "Invalid API key: You must be granted a valid key."})
+		return false
+	}
+
+	return true
+}
+
+// serveTmdb serves a TMDB search request: a multi search if resultType is empty, or a single-type search
+// filtered by year otherwise, whose results don't report their media type.
+func (fp *FakeProvider) serveTmdb(w http.ResponseWriter, r *http.Request, resultType search.ResultType, year string) {
+	if !fp.admitTmdb(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	page, err := strconv.Atoi(query.Get("page"))
+	if query.Get("page") == "" {
+		page, err = 1, nil
+	}
+
+	if err != nil || page < 1 || page > 500 {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]any{"success": false, "status_code": 22, "status_message": "Invalid page: Pages start at 1 and max at 500. They are expected to be an integer."})
+		return
+	}
+
+	opts := search.SearchOptions{SafeSearch: query.Get("include_adult") == "false", Type: resultType, Year: year}
+	matches := fp.match(query.Get("query"), opts)
+
+	// TMDB's multi search doesn't return episodes
+	var titles []search.SearchResult
+	for _, match := range matches {
+		if match.Type != search.Episode {
+			titles = append(titles, match)
+		}
+	}
+
+	results := []map[string]any{}
+	for i, title := range paginate(titles, page, fp.config.TmdbPageSize) {
+		id, err := strconv.Atoi(title.TmdbID)
+		if err != nil {
+			id = (page-1)*fp.config.TmdbPageSize + i + 1
+		}
+
+		result := map[string]any{
+			"id":                id,
+			"adult":             title.Adult,
+			"original_language": title.OriginalLanguage,
+			"poster_path":       nullable(title.PosterURL),
+		}
+
+		originalTitle := title.OriginalTitle
+		if originalTitle == "" {
+			originalTitle = title.Title
+		}
+
+		date := ""
+		if title.Year != "" {
+			date = title.Year + "-01-01"
+		}
+
+		if title.Type == search.Series {
+			result["media_type"], result["name"], result["original_name"], result["first_air_date"] = "tv", title.Title, originalTitle, date
+		} else {
+			result["media_type"], result["title"], result["original_title"], result["release_date"] = "movie", title.Title, originalTitle, date
+		}
+
+		if resultType != "" {
+			delete(result, "media_type")
+		}
+
+		results = append(results, result)
+	}
+
+	writeJSON(w, map[string]any{
+		"page":          page,
+		"results":       results,
+		"total_pages":   max(1, (len(titles)+fp.config.TmdbPageSize-1)/fp.config.TmdbPageSize),
+		"total_results": len(titles),
+	})
+}
+
+// serveOmdb serves an OMDB search request.
+func (fp *FakeProvider) serveOmdb(w http.ResponseWriter, r *http.Request) {
+	if status := fp.nextInjected(); status != 0 {
+		w.WriteHeader(status)
+		if status == http.StatusTooManyRequests {
+			writeJSON(w, map[string]any{"Response": "False", "Error": "Request limit reached!"})
+		} else {
+			writeJSON(w, map[string]any{"Response": "False", "Error": "Internal Server Error"})
+		}
+		return
+	}
+
+	// OMDB parameter names are case-insensitive
+	query := map[string]string{}
+	for name, values := range r.URL.Query() {
+		query[strings.ToLower(name)] = values[0]
+	}
+
+	if query["apikey"] == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		writeJSON(w, map[string]any{"Response": "False", "Error": "No API key provided."})
+		return
+	}
+
+	if !validKey(query["apikey"], fp.config.OmdbAPIKey) {
+		w.WriteHeader(http.StatusUnauthorized)
+		writeJSON(w, map[string]any{"Response": "False", "Error": "Invalid API key!"})
+		return
+	}
+
+	if query["s"] == "" {
+		writeJSON(w, map[string]any{"Response": "False", "Error": "Incorrect IMDb ID."})
+		return
+	}
+
+	page := 1
+	if value, ok := query["page"]; ok {
+		var err error
+		if page, err = strconv.Atoi(value); err != nil || page < 1 || page > 100 {
+			writeJSON(w, map[string]any{"Response": "False", "Error": "The offset specified in a OFFSET clause may not be negative."})
+			return
+		}
+	}
+
+	opts := search.SearchOptions{Year: query["y"], Type: search.ResultType(query["type"])}
+	matches := fp.match(query["s"], opts)
+
+	results := []map[string]any{}
+	for _, title := range paginate(matches, page, fp.config.OmdbPageSize) {
+		poster := title.PosterURL
+		if poster == "" {
+			poster = "N/A"
+		}
+
+		results = append(results, map[string]any{
+			"Title":  title.Title,
+			"Year":   title.Year,
+			"imdbID": title.ImdbID,
+			"Type":   title.Type,
+			"Poster": poster,
+		})
+	}
+
+	if len(results) == 0 {
+		writeJSON(w, map[string]any{"Response": "False", "Error": "Movie not found!"})
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"Search":       results,
+		"totalResults": strconv.Itoa(len(matches)),
+		"Response":     "True",
+	})
+}
+
+// match returns all the titles in the catalogue matching a query. It bypasses FakeSearcher.Search,
+// which records every call, so that a long-running provider doesn't grow without bound.
+func (fp *FakeProvider) match(query string, opts search.SearchOptions) []search.SearchResult {
+	return fp.searcher.match(query, len(fp.searcher.catalogue)+1, opts)
+}
+
+// paginate returns the titles on a 1-based page.
+func paginate(titles []search.SearchResult, page, pageSize int) []search.SearchResult {
+	start := min((page-1)*pageSize, len(titles))
+	end := min(start+pageSize, len(titles))
+
+	return titles[start:end]
+}
+
+// nullable returns nil for an empty string, which encodes as JSON null.
+func nullable(value string) any {
+	if value == "" {
+		return nil
+	}
+
+	return value
+}
+
+// writeJSON writes a value as JSON. The responses only hold encodable values, so encoding cannot fail.
+func writeJSON(w http.ResponseWriter, value any) {
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,203 @@
+package searchtest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jdahan/gogettitles/search"
+	"github.com/jdahan/gogettitles/search/searchtest"
+)
+
+const (
+	tmdbKey = "tmdb-test-key"
+	omdbKey = "omdb-test-key"
+)
+
+func newFakeProviderServer(t *testing.T, config searchtest.FakeProviderConfig) (*searchtest.FakeProvider, *httptest.Server) {
+	t.Helper()
+
+	config.TmdbAPIKey, config.OmdbAPIKey = tmdbKey, omdbKey
+	provider := searchtest.NewFakeProvider(config)
+	server := httptest.NewServer(provider)
+	t.Cleanup(server.Close)
+
+	return provider, server
+}
+
+func TestFakeProvider_Search(t *testing.T) {
+	_, server := newFakeProviderServer(t, searchtest.FakeProviderConfig{})
+
+	searchers := map[string]search.Searcher{
+		"tmdb": search.NewTmdbSearcher(tmdbKey, server.Client(), search.WithBaseURL(server.URL)),
+		"omdb": search.NewOmdbSearcher(omdbKey, server.Client(), search.WithBaseURL(server.URL)),
+	}
+
+	for name, searcher := range searchers {
+		t.Run(name, func(t *testing.T) {
+			results, err := searcher.Search(context.Background(), "star wars", 10)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := []string{"Star Wars (1977)", "Star Wars: The Empire Strikes Back (1980)", "Star Wars: Andor (2022)"}
+			if got := titles(results); !slices.Equal(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+			if results[2].Type != search.Series {
+				t.Errorf("expected Andor to be a series, got %q", results[2].Type)
+			}
+		})
+	}
+}
+
+func TestFakeProvider_Search_Pagination(t *testing.T) {
+	var catalogue []search.SearchResult
+	for _, title := range []string{"Alien", "Aliens", "Alien 3", "Alien Resurrection", "Alien: Covenant", "Alien: Romulus", "Alien Nation"} {
+		catalogue = append(catalogue, search.SearchResult{Title: title, Year: "1986", ImdbID: "tt0090605", TmdbID: "679", Type: search.Movie})
+	}
+
+	provider, server := newFakeProviderServer(t, searchtest.FakeProviderConfig{Catalogue: catalogue, TmdbPageSize: 2, OmdbPageSize: 3})
+
+	tmdb := search.NewTmdbSearcher(tmdbKey, server.Client(), search.WithBaseURL(server.URL))
+	results, err := tmdb.Search(context.Background(), "alien", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 5 || provider.Requests() != 3 {
+		t.Errorf("expected 5 results over 3 pages, got %d results over %d pages", len(results), provider.Requests())
+	}
+
+	omdb := search.NewOmdbSearcher(omdbKey, server.Client(), search.WithBaseURL(server.URL))
+	results, err = omdb.Search(context.Background(), "alien", 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != len(catalogue) || provider.Requests() != 6 {
+		t.Errorf("expected all %d results over 3 pages, got %d results over %d pages", len(catalogue), len(results), provider.Requests()-3)
+	}
+}
+
+func TestFakeProvider_Search_Filters(t *testing.T) {
+	_, server := newFakeProviderServer(t, searchtest.FakeProviderConfig{})
+	omdb := search.NewOmdbSearcher(omdbKey, server.Client(), search.WithBaseURL(server.URL))
+
+	ctx := search.WithSearchOptions(context.Background(), search.SearchOptions{Type: search.Series})
+	results, err := omdb.Search(ctx, "fargo", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := titles(results); !slices.Equal(got, []string{"Fargo (2014)"}) {
+		t.Errorf("expected the type parameter to filter results, got %v", got)
+	}
+}
+
+func TestFakeProvider_Search_TmdbFilters(t *testing.T) {
+	provider, server := newFakeProviderServer(t, searchtest.FakeProviderConfig{})
+	tmdb := search.NewTmdbSearcher(tmdbKey, server.Client(), search.WithBaseURL(server.URL))
+
+	for _, tt := range []struct {
+		opts search.SearchOptions
+		want []string
+	}{
+		{search.SearchOptions{Type: search.Series}, []string{"Fargo (2014)"}},
+		{search.SearchOptions{Type: search.Movie, Year: "1996"}, []string{"Fargo (1996)"}},
+		{search.SearchOptions{Type: search.Series, Year: "1996"}, nil},
+	} {
+		results, err := tmdb.Search(search.WithSearchOptions(context.Background(), tt.opts), "fargo", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := titles(results); !slices.Equal(got, tt.want) {
+			t.Errorf("%+v: expected %v, got %v", tt.opts, tt.want, got)
+		}
+	}
+
+	if provider.Requests() != 3 {
+		t.Errorf("expected a single request per search, got %d", provider.Requests())
+	}
+}
+
+func TestFakeProvider_Search_NoResults(t *testing.T) {
+	_, server := newFakeProviderServer(t, searchtest.FakeProviderConfig{})
+
+	for name, searcher := range map[string]search.Searcher{
+		"tmdb": search.NewTmdbSearcher(tmdbKey, server.Client(), search.WithBaseURL(server.URL)),
+		"omdb": search.NewOmdbSearcher(omdbKey, server.Client(), search.WithBaseURL(server.URL)),
+	} {
+		results, err := searcher.Search(context.Background(), "zzz", 10)
+		if err != nil || len(results) != 0 {
+			t.Errorf("%s: expected no results and no error, got %+v, %v", name, results, err)
+		}
+	}
+}
+
+func TestFakeProvider_InvalidAPIKey(t *testing.T) {
+	_, server := newFakeProviderServer(t, searchtest.FakeProviderConfig{})
+
+	for name, searcher := range map[string]search.Searcher{
+		"tmdb": search.NewTmdbSearcher("wrong", server.Client(), search.WithBaseURL(server.URL)),
+		"omdb": search.NewOmdbSearcher("wrong", server.Client(), search.WithBaseURL(server.URL)),
+	} {
+		_, err := searcher.Search(context.Background(), "matrix", 10)
+
+		var providerErr *search.SearchProviderError
+		if !errors.As(err, &providerErr) || !strings.Contains(strings.ToLower(err.Error()), "invalid api key") {
+			t.Errorf("%s: expected an invalid API key error, got %v", name, err)
+		}
+	}
+}
+
+func TestFakeProvider_Inject(t *testing.T) {
+	provider, server := newFakeProviderServer(t, searchtest.FakeProviderConfig{})
+
+	for name, searcher := range map[string]search.Searcher{
+		"tmdb": search.NewTmdbSearcher(tmdbKey, server.Client(), search.WithBaseURL(server.URL)),
+		"omdb": search.NewOmdbSearcher(omdbKey, server.Client(), search.WithBaseURL(server.URL)),
+	} {
+		provider.Inject(http.StatusTooManyRequests, 1)
+		provider.Inject(http.StatusServiceUnavailable, 1)
+
+		var rateLimitErr *search.RateLimitError
+		if _, err := searcher.Search(context.Background(), "matrix", 10); !errors.As(err, &rateLimitErr) {
+			t.Errorf("%s: expected RateLimitError, got %v", name, err)
+		}
+		if _, err := searcher.Search(context.Background(), "matrix", 10); err == nil {
+			t.Errorf("%s: expected an error for the injected 503", name)
+		}
+		if _, err := searcher.Search(context.Background(), "matrix", 10); err != nil {
+			t.Errorf("%s: expected injected failures to be used up, got %v", name, err)
+		}
+	}
+}
+
+func TestFakeProvider_InjectOverHTTP(t *testing.T) {
+	_, server := newFakeProviderServer(t, searchtest.FakeProviderConfig{})
+
+	resp, err := server.Client().PostForm(server.URL+"/_fakeprovider/inject", url.Values{"status": {"500"}, "count": {"2"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", resp.StatusCode)
+	}
+
+	tmdb := search.NewTmdbSearcher(tmdbKey, server.Client(), search.WithBaseURL(server.URL))
+	for i := 0; i < 2; i++ {
+		var providerErr *search.SearchProviderError
+		if _, err := tmdb.Search(context.Background(), "matrix", 10); !errors.As(err, &providerErr) {
+			t.Errorf("expected SearchProviderError, got %v", err)
+		}
+	}
+	if _, err := tmdb.Search(context.Background(), "matrix", 10); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
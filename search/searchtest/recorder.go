@@ -0,0 +1,379 @@
+package searchtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// cassetteVersion is the version of the cassette file format.
+const cassetteVersion = 1
+
+// redacted replaces secrets in cassettes.
+const redacted = "REDACTED"
+
+// RecordEnv is the environment variable that switches ModeFromEnv to record mode when set to a non-empty value.
+const RecordEnv = "GOGETTITLES_RECORD"
+
+// A RecorderMode selects whether a Recorder records real traffic or replays it.
+type RecorderMode int
+
+const (
+	// ModeReplay serves responses from the cassette, failing requests that aren't in it.
+	ModeReplay RecorderMode = iota
+	// ModeRecord sends requests to the real provider and records them to the cassette.
+	ModeRecord
+)
+
+// ModeFromEnv returns ModeRecord if the RecordEnv environment variable is set, and ModeReplay otherwise,
+// so fixtures can be refreshed by running the tests with e.g. GOGETTITLES_RECORD=1.
+func ModeFromEnv() RecorderMode {
+	if os.Getenv(RecordEnv) != "" {
+		return ModeRecord
+	}
+
+	return ModeReplay
+}
+
+// RecorderConfig holds the configuration of a Recorder.
+type RecorderConfig struct {
+	// Mode selects whether traffic is recorded or replayed.
+	Mode RecorderMode
+	// Transport sends requests in record mode. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// SecretParams are the query parameters scrubbed from cassettes and ignored when matching, compared
+	// case-insensitively. Defaults to the API key parameters of TMDB and OMDB ("api_key" and "apikey").
+	SecretParams []string
+	// SecretHeaders are the headers scrubbed from cassettes. Defaults to "Authorization".
+	SecretHeaders []string
+	// Secrets are values, such as API keys, replaced anywhere they appear in recorded URLs, headers, and bodies.
+	Secrets []string
+}
+
+// An Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// A RecordedRequest is a request in a cassette.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+}
+
+// A RecordedResponse is a response in a cassette.
+type RecordedResponse struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body"`
+}
+
+// cassette is the serialized form of a cassette file.
+type cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// UnmatchedRequestError is an error type that is returned when a replayed request isn't in the cassette.
+type UnmatchedRequestError struct {
+	method string
+	url    string
+}
+
+// NewUnmatchedRequestError creates a new UnmatchedRequestError for the specified request.
+func NewUnmatchedRequestError(method, url string) *UnmatchedRequestError {
+	return &UnmatchedRequestError{method: method, url: url}
+}
+
+// Error returns the error message associated with the UnmatchedRequestError.
+func (e *UnmatchedRequestError) Error() string {
+	return fmt.Sprintf("no recorded interaction matches %s %s", e.method, e.url)
+}
+
+// A Recorder is an http.RoundTripper that records provider traffic to a cassette file, or replays it,
+// so provider tests can run deterministically against real responses. API keys are scrubbed from cassettes.
+//
+// In replay mode, each request is matched by method and URL (ignoring secret parameters and the order
+// of parameters) to the first recorded interaction not yet replayed, so repeated requests replay in order.
+type Recorder struct {
+	path   string
+	config RecorderConfig
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// NewRecorder creates a new Recorder.
+//
+// Parameters:
+//   - path: The cassette file to record to or replay from.
+//   - config: The configuration of the Recorder.
+//
+// Returns:
+//   - *Recorder: A new instance of Recorder.
+//   - error: An error if replaying and the cassette cannot be read.
+func NewRecorder(path string, config RecorderConfig) (*Recorder, error) {
+	if config.Transport == nil {
+		config.Transport = http.DefaultTransport
+	}
+
+	if config.SecretParams == nil {
+		config.SecretParams = []string{"api_key", "apikey"}
+	}
+
+	if config.SecretHeaders == nil {
+		config.SecretHeaders = []string{"Authorization"}
+	}
+
+	recorder := &Recorder{path: path, config: config}
+	if config.Mode == ModeRecord {
+		return recorder, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+
+	if c.Version != cassetteVersion {
+		return nil, fmt.Errorf("cassette %s has version %d, expected %d; re-record it", path, c.Version, cassetteVersion)
+	}
+
+	recorder.interactions = c.Interactions
+	recorder.replayed = make([]bool, len(c.Interactions))
+
+	return recorder, nil
+}
+
+// Client returns an HTTP client using the Recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays a request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.config.Mode == ModeRecord {
+		return r.record(req)
+	}
+
+	return r.replay(req)
+}
+
+// record sends a request and records it and its response.
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.config.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     r.scrub(r.scrubURL(req.URL).String()),
+			Headers: r.scrubHeaders(req.Header),
+		},
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: r.scrubHeaders(resp.Header),
+			Body:    r.scrub(string(body)),
+		},
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay serves a request from the first matching interaction not yet replayed.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	key := r.matchKey(req.Method, req.URL)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.replayed[i] {
+			continue
+		}
+
+		recorded, err := url.Parse(interaction.Request.URL)
+		if err != nil || r.matchKey(interaction.Request.Method, recorded) != key {
+			continue
+		}
+
+		r.replayed[i] = true
+
+		header := interaction.Response.Headers.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, NewUnmatchedRequestError(req.Method, r.scrubURL(req.URL).String())
+}
+
+// Save writes the recorded interactions to the cassette file, creating its directory if needed.
+// It does nothing in replay mode.
+//
+// Returns:
+//   - error: An error if the cassette cannot be written.
+func (r *Recorder) Save() error {
+	if r.config.Mode != ModeRecord {
+		return nil
+	}
+
+	// Keep URLs readable: "&" would otherwise be escaped as "\u0026"
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	r.mu.Lock()
+	err := encoder.Encode(cassette{Version: cassetteVersion, Interactions: r.interactions})
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// Unplayed returns the recorded interactions that haven't been replayed, e.g. to check a test made every request.
+func (r *Recorder) Unplayed() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unplayed []Interaction
+	for i, interaction := range r.interactions {
+		if i < len(r.replayed) && !r.replayed[i] {
+			unplayed = append(unplayed, interaction)
+		}
+	}
+
+	return unplayed
+}
+
+// isSecretParam reports whether a query parameter holds a secret.
+func (r *Recorder) isSecretParam(name string) bool {
+	for _, secret := range r.config.SecretParams {
+		if strings.EqualFold(name, secret) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scrubURL returns a copy of a URL with its secret parameters redacted.
+func (r *Recorder) scrubURL(u *url.URL) *url.URL {
+	scrubbed := *u
+	params := u.Query()
+	for name := range params {
+		if r.isSecretParam(name) {
+			params[name] = []string{redacted}
+		}
+	}
+
+	scrubbed.RawQuery = params.Encode()
+
+	return &scrubbed
+}
+
+// scrubHeaders returns a copy of headers with secret headers redacted and secret values replaced.
+func (r *Recorder) scrubHeaders(headers http.Header) http.Header {
+	scrubbed := http.Header{}
+	for name, values := range headers {
+		for _, value := range values {
+			scrubbed.Add(name, r.scrub(value))
+		}
+	}
+
+	for _, name := range r.config.SecretHeaders {
+		if scrubbed.Get(name) != "" {
+			scrubbed.Set(name, redacted)
+		}
+	}
+
+	return scrubbed
+}
+
+// scrub replaces the configured secrets in a value.
+func (r *Recorder) scrub(value string) string {
+	for _, secret := range r.config.Secrets {
+		if secret != "" {
+			value = strings.ReplaceAll(value, secret, redacted)
+		}
+	}
+
+	return value
+}
+
+// matchKey returns the key requests are matched by: the method, and the URL without secret parameters,
+// with parameters sorted.
+func (r *Recorder) matchKey(method string, u *url.URL) string {
+	params := u.Query()
+	for name := range params {
+		if r.isSecretParam(name) {
+			delete(params, name)
+		}
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString("&" + name + "=" + strings.Join(params[name], ","))
+	}
+
+	return method + " " + u.Scheme + "://" + u.Host + u.Path + "?" + sb.String()
+}
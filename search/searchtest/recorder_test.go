@@ -0,0 +1,174 @@
+package searchtest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jdahan/gogettitles/search"
+	"github.com/jdahan/gogettitles/search/searchtest"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	_, server := newFakeProviderServer(t, searchtest.FakeProviderConfig{})
+	cassette := filepath.Join(t.TempDir(), "cassettes", "search.json")
+
+	recorder, err := searchtest.NewRecorder(cassette, searchtest.RecorderConfig{
+		Mode:      searchtest.ModeRecord,
+		Transport: server.Client().Transport,
+		Secrets:   []string{tmdbKey, omdbKey},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tmdb := search.NewTmdbSearcher(tmdbKey, recorder.Client(), search.WithBaseURL(server.URL))
+	omdb := search.NewOmdbSearcher(omdbKey, recorder.Client(), search.WithBaseURL(server.URL))
+
+	recordedTmdb, err := tmdb.Search(context.Background(), "matrix", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recordedOmdb, err := omdb.Search(context.Background(), "star wars", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, key := range []string{tmdbKey, omdbKey} {
+		if strings.Contains(string(data), key) {
+			t.Errorf("expected API key %q to be scrubbed from the cassette", key)
+		}
+	}
+
+	// Replaying needs neither the server nor the real keys
+	server.Close()
+
+	replayer, err := searchtest.NewRecorder(cassette, searchtest.RecorderConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tmdb = search.NewTmdbSearcher("other-key", replayer.Client(), search.WithBaseURL(server.URL))
+	omdb = search.NewOmdbSearcher("other-key", replayer.Client(), search.WithBaseURL(server.URL))
+
+	replayedTmdb, err := tmdb.Search(context.Background(), "matrix", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	replayedOmdb, err := omdb.Search(context.Background(), "star wars", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := titles(replayedTmdb), titles(recordedTmdb); !slices.Equal(got, want) {
+		t.Errorf("expected replayed TMDB results %v, got %v", want, got)
+	}
+
+	if got, want := titles(replayedOmdb), titles(recordedOmdb); !slices.Equal(got, want) {
+		t.Errorf("expected replayed OMDB results %v, got %v", want, got)
+	}
+
+	if unplayed := replayer.Unplayed(); len(unplayed) != 0 {
+		t.Errorf("expected every interaction to be replayed, got %d unplayed", len(unplayed))
+	}
+}
+
+func TestRecorder_Replay_Unmatched(t *testing.T) {
+	_, server := newFakeProviderServer(t, searchtest.FakeProviderConfig{})
+	cassette := filepath.Join(t.TempDir(), "search.json")
+
+	recorder, err := searchtest.NewRecorder(cassette, searchtest.RecorderConfig{Mode: searchtest.ModeRecord, Transport: server.Client().Transport})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	searcher := search.NewTmdbSearcher(tmdbKey, recorder.Client(), search.WithBaseURL(server.URL))
+	if _, err := searcher.Search(context.Background(), "matrix", 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	replayer, err := searchtest.NewRecorder(cassette, searchtest.RecorderConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	searcher = search.NewTmdbSearcher(tmdbKey, replayer.Client(), search.WithBaseURL(server.URL))
+
+	// Searchers report transport errors as provider errors, keeping only their message
+	if _, err := searcher.Search(context.Background(), "heat", 3); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Fatalf("expected unmatched request error for an unrecorded query, got %v", err)
+	}
+
+	if _, err := searcher.Search(context.Background(), "matrix", 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Each interaction replays once
+	if _, err := searcher.Search(context.Background(), "matrix", 3); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Fatalf("expected unmatched request error for a repeated request, got %v", err)
+	}
+}
+
+func TestRecorder_RoundTrip_Unmatched(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(cassette, []byte(`{"version":1,"interactions":[]}`), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	replayer, err := searchtest.NewRecorder(cassette, searchtest.RecorderConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://www.omdbapi.com/?apikey=secret&s=heat", nil)
+
+	var unmatchedErr *searchtest.UnmatchedRequestError
+	_, err = replayer.RoundTrip(req)
+	if !errors.As(err, &unmatchedErr) {
+		t.Fatalf("expected unmatched request error, got %v", err)
+	}
+
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected API key to be scrubbed from the error, got %v", err)
+	}
+}
+
+func TestNewRecorder_MissingCassette(t *testing.T) {
+	_, err := searchtest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), searchtest.RecorderConfig{})
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected missing cassette error, got %v", err)
+	}
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv(searchtest.RecordEnv, "")
+	if mode := searchtest.ModeFromEnv(); mode != searchtest.ModeReplay {
+		t.Errorf("expected replay mode, got %v", mode)
+	}
+
+	t.Setenv(searchtest.RecordEnv, "1")
+	if mode := searchtest.ModeFromEnv(); mode != searchtest.ModeRecord {
+		t.Errorf("expected record mode, got %v", mode)
+	}
+}
@@ -0,0 +1,250 @@
+package search
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// A Suggester is a service that suggests titles without a query, such as trending or popular titles,
+// so autocomplete can show something useful before the user has typed.
+type Suggester interface {
+	// Suggest returns suggested titles, most relevant first.
+	//
+	// Parameters:
+	//   - ctx: The context for controlling cancellation and deadlines.
+	//   - maxResults: The maximum number of suggestions to return.
+	//
+	// Returns:
+	//   - []SearchResult: A slice containing the suggestions.
+	//   - error: An error if the suggestions could not be retrieved.
+	Suggest(ctx context.Context, maxResults int) ([]SearchResult, error)
+}
+
+// RefreshingSuggesterConfig holds the configuration of a RefreshingSuggester.
+type RefreshingSuggesterConfig struct {
+	// Interval is how often suggestions are refreshed. Defaults to 1 hour.
+	Interval time.Duration
+	// Size is the number of suggestions fetched and cached. Defaults to 20.
+	Size int
+	// Timeout bounds each refresh. Defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// A RefreshingSuggester is a Suggester decorator that caches suggestions and refreshes them in the background,
+// so suggestions are served without waiting on the provider.
+type RefreshingSuggester struct {
+	suggester Suggester
+	config    RefreshingSuggesterConfig
+
+	mu          sync.RWMutex
+	suggestions []SearchResult
+	loaded      bool
+	loading     *suggestionLoad
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewRefreshingSuggester creates a new RefreshingSuggester and starts refreshing suggestions in the background.
+// Close must be called to stop refreshing.
+//
+// Parameters:
+//   - suggester: The Suggester to retrieve suggestions from.
+//   - config: The configuration of the RefreshingSuggester.
+//
+// Returns:
+//   - *RefreshingSuggester: A new instance of RefreshingSuggester.
+func NewRefreshingSuggester(suggester Suggester, config RefreshingSuggesterConfig) *RefreshingSuggester {
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+
+	if config.Size <= 0 {
+		config.Size = 20
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	rs := &RefreshingSuggester{
+		suggester: suggester,
+		config:    config,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	go rs.run()
+
+	return rs
+}
+
+// Suggest returns the cached suggestions, retrieving them first if they haven't been loaded yet.
+// Callers waiting on the first load share a single request, so a provider that is down at startup
+// isn't sent a request per caller.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - maxResults: The maximum number of suggestions to return.
+//
+// Returns:
+//   - []SearchResult: A slice containing the suggestions, owned by the caller.
+//   - error: An error if the suggestions had not been loaded and could not be retrieved.
+func (rs *RefreshingSuggester) Suggest(ctx context.Context, maxResults int) ([]SearchResult, error) {
+	if maxResults <= 0 {
+		return nil, NewInvalidMaxResultsError()
+	}
+
+	rs.mu.RLock()
+	suggestions, loaded := rs.suggestions, rs.loaded
+	rs.mu.RUnlock()
+
+	if !loaded {
+		load := rs.load()
+
+		select {
+		case <-load.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if load.err != nil {
+			return nil, load.err
+		}
+
+		rs.mu.RLock()
+		suggestions = rs.suggestions
+		rs.mu.RUnlock()
+	}
+
+	return slices.Clone(suggestions[:min(maxResults, len(suggestions))]), nil
+}
+
+// Close stops refreshing suggestions in the background.
+func (rs *RefreshingSuggester) Close() {
+	close(rs.stop)
+	<-rs.done
+}
+
+// run refreshes the suggestions every interval until the RefreshingSuggester is closed.
+func (rs *RefreshingSuggester) run() {
+	defer close(rs.done)
+
+	ticker := time.NewTicker(rs.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		load := rs.load()
+
+		select {
+		case <-load.done:
+			if load.err != nil {
+				// Keep serving the previous suggestions until the next refresh
+				log.Printf("Failed to refresh suggestions: %v\n", load.err)
+			}
+		case <-rs.stop:
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-rs.stop:
+			return
+		}
+	}
+}
+
+// suggestionLoad is a retrieval of the suggestions shared by everyone waiting on it.
+type suggestionLoad struct {
+	done chan struct{}
+	err  error
+}
+
+// load starts retrieving the suggestions and caching them, or joins the retrieval already in flight.
+// The retrieval is bounded by the configured Timeout rather than by any caller's context.
+func (rs *RefreshingSuggester) load() *suggestionLoad {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.loading != nil {
+		return rs.loading
+	}
+
+	load := &suggestionLoad{done: make(chan struct{})}
+	rs.loading = load
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), rs.config.Timeout)
+		defer cancel()
+
+		suggestions, err := rs.suggester.Suggest(ctx, rs.config.Size)
+
+		rs.mu.Lock()
+		if err == nil {
+			rs.suggestions, rs.loaded = suggestions, true
+		}
+		rs.loading = nil
+		rs.mu.Unlock()
+
+		load.err = err
+		close(load.done)
+	}()
+
+	return load
+}
+
+// A SuggestingSearcher is a Searcher decorator that returns suggestions for empty or very short queries,
+// which would otherwise return no useful results.
+type SuggestingSearcher struct {
+	searcher       Searcher
+	suggester      Suggester
+	minQueryLength int
+}
+
+// NewSuggestingSearcher creates a new SuggestingSearcher.
+//
+// Parameters:
+//   - searcher: The Searcher to send queries to.
+//   - suggester: The Suggester to return suggestions from for short queries.
+//   - minQueryLength: The minimum number of characters a query needs to be searched. Defaults to 2.
+//
+// Returns:
+//   - *SuggestingSearcher: A new instance of SuggestingSearcher.
+func NewSuggestingSearcher(searcher Searcher, suggester Suggester, minQueryLength int) *SuggestingSearcher {
+	if minQueryLength <= 0 {
+		minQueryLength = 2
+	}
+
+	return &SuggestingSearcher{
+		searcher:       searcher,
+		suggester:      suggester,
+		minQueryLength: minQueryLength,
+	}
+}
+
+// Search returns suggestions if the query is shorter than the minimum length, and search results otherwise.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - query: The search query string.
+//   - maxResults: The maximum number of search results to return.
+//
+// Returns:
+//   - []SearchResult: A slice containing the suggestions or search results.
+//   - error: An error if the search operation fails.
+func (ss *SuggestingSearcher) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	if maxResults <= 0 {
+		return nil, NewInvalidMaxResultsError()
+	}
+
+	if utf8.RuneCountInString(strings.TrimSpace(query)) < ss.minQueryLength {
+		return ss.suggester.Suggest(ctx, maxResults)
+	}
+
+	return ss.searcher.Search(ctx, query, maxResults)
+}
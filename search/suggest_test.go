@@ -0,0 +1,232 @@
+package search_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/jdahan/gogettitles/search"
+)
+
+// stubSuggester suggests fixed titles after a latency, and counts its calls.
+type stubSuggester struct {
+	calls   atomic.Int32
+	err     error
+	latency time.Duration
+}
+
+func (s *stubSuggester) Suggest(ctx context.Context, maxResults int) ([]search.SearchResult, error) {
+	s.calls.Add(1)
+	time.Sleep(s.latency)
+	if s.err != nil {
+		return nil, s.err
+	}
+	suggestions := []search.SearchResult{{Title: "Moana 2"}, {Title: "Gladiator II"}, {Title: "Wicked"}}
+	return suggestions[:min(maxResults, len(suggestions))], nil
+}
+
+func TestTmdbSuggester_Suggest_Trending(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	mockData, err := loadMockResponse("tmdb_trending_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://api.themoviedb.org").
+		Get("/3/trending/all/day").
+		MatchParam("page", "1").
+		MatchHeader("Authorization", "Bearer "+testAPIKey).
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	suggestions, err := search.NewTmdbSuggester(searcher, search.TmdbTrendingToday).Suggest(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The person is skipped
+	if len(suggestions) != 3 {
+		t.Fatalf("expected 3 suggestions, got %d", len(suggestions))
+	}
+	if suggestions[0].Title != "Moana 2" || suggestions[0].Type != search.Movie || suggestions[0].Year != "2024" {
+		t.Errorf("unexpected suggestion: %+v", suggestions[0])
+	}
+	if suggestions[1].Type != search.Series || suggestions[1].ProviderId != "202879" {
+		t.Errorf("unexpected suggestion: %+v", suggestions[1])
+	}
+}
+
+func TestTmdbSuggester_Suggest_PopularMoviesPagination(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	mockData, err := loadMockResponse("tmdb_popular_movies_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	for _, page := range []string{"1", "2"} {
+		gock.New("https://api.themoviedb.org").
+			Get("/3/movie/popular").
+			MatchParam("page", page).
+			MatchParam("language", "de-DE").
+			Reply(200).
+			JSON(json.RawMessage(mockData))
+	}
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient, search.WithLanguage("de-DE"))
+	suggestions, err := search.NewTmdbSuggester(searcher, search.TmdbPopularMovies).Suggest(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(suggestions) != 3 {
+		t.Fatalf("expected 3 suggestions, got %d", len(suggestions))
+	}
+	for _, suggestion := range suggestions {
+		if suggestion.Type != search.Movie {
+			t.Errorf("expected popular movies to be typed as movies, got %+v", suggestion)
+		}
+	}
+}
+
+func TestTmdbSuggester_Suggest_EmptyPage(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	gock.New("https://api.themoviedb.org").
+		Get("/3/trending/all/day").
+		MatchParam("page", "1").
+		Reply(200).
+		JSON(json.RawMessage(`{"page":1,"results":[],"total_pages":500,"total_results":10000}`))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	suggestions, err := search.NewTmdbSuggester(searcher, search.TmdbTrendingToday).Suggest(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(suggestions) != 0 || !gock.IsDone() {
+		t.Errorf("expected a single empty page, got %d suggestions", len(suggestions))
+	}
+}
+
+func TestTmdbSuggester_Suggest_PageCap(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	// Pages of people hold no suggestions, however many TMDB lists
+	gock.New("https://api.themoviedb.org").
+		Get("/3/trending/all/day").
+		Times(4).
+		Reply(200).
+		JSON(json.RawMessage(`{"results":[{"id":9339,"name":"Lana Wachowski","media_type":"person"}],"total_pages":500}`))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	suggestions, err := search.NewTmdbSuggester(searcher, search.TmdbTrendingToday).Suggest(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(suggestions) != 0 || !gock.IsDone() {
+		t.Errorf("expected 4 pages without suggestions, got %d suggestions", len(suggestions))
+	}
+}
+
+func TestRefreshingSuggester_Suggest_Cached(t *testing.T) {
+	suggester := &stubSuggester{}
+	refreshing := search.NewRefreshingSuggester(suggester, search.RefreshingSuggesterConfig{Interval: time.Hour})
+	defer refreshing.Close()
+
+	for i := 0; i < 3; i++ {
+		suggestions, err := refreshing.Suggest(context.Background(), 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(suggestions) != 2 {
+			t.Errorf("expected 2 suggestions, got %d", len(suggestions))
+		}
+	}
+
+	// Callers waiting on the first load share it with the background refresh
+	if calls := suggester.calls.Load(); calls != 1 {
+		t.Errorf("expected suggestions to be cached, got %d calls", calls)
+	}
+}
+
+func TestRefreshingSuggester_Suggest_RefreshesInBackground(t *testing.T) {
+	suggester := &stubSuggester{}
+	refreshing := search.NewRefreshingSuggester(suggester, search.RefreshingSuggesterConfig{Interval: 5 * time.Millisecond})
+
+	time.Sleep(30 * time.Millisecond)
+	refreshing.Close()
+
+	if calls := suggester.calls.Load(); calls < 3 {
+		t.Errorf("expected suggestions to be refreshed periodically, got %d calls", calls)
+	}
+}
+
+func TestRefreshingSuggester_Suggest_Error(t *testing.T) {
+	suggester := &stubSuggester{err: search.NewSearchProviderError("down")}
+	refreshing := search.NewRefreshingSuggester(suggester, search.RefreshingSuggesterConfig{})
+	defer refreshing.Close()
+
+	_, err := refreshing.Suggest(context.Background(), 2)
+	var spErr *search.SearchProviderError
+	if err == nil || !errors.As(err, &spErr) {
+		t.Fatalf("expected search provider error, got %v", err)
+	}
+}
+
+func TestRefreshingSuggester_Suggest_CoalescesLoads(t *testing.T) {
+	suggester := &stubSuggester{err: search.NewSearchProviderError("down"), latency: 50 * time.Millisecond}
+	refreshing := search.NewRefreshingSuggester(suggester, search.RefreshingSuggesterConfig{})
+	defer refreshing.Close()
+
+	// Callers arriving while the provider is down share the load in flight
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := refreshing.Suggest(context.Background(), 2); err == nil {
+				t.Error("expected an error")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := suggester.calls.Load(); calls != 1 {
+		t.Errorf("expected a single load, got %d calls", calls)
+	}
+}
+
+func TestSuggestingSearcher_Search(t *testing.T) {
+	searcher := &countingSearcher{}
+	suggester := &stubSuggester{}
+	suggesting := search.NewSuggestingSearcher(searcher, suggester, 2)
+
+	for _, query := range []string{"", " ", "s", "é "} {
+		results, err := suggesting.Search(context.Background(), query, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 3 || results[0].Title != "Moana 2" {
+			t.Errorf("expected suggestions for %q, got %+v", query, results)
+		}
+	}
+
+	results, err := suggesting.Search(context.Background(), "st", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "st" || searcher.calls.Load() != 1 {
+		t.Errorf("expected search results, got %+v", results)
+	}
+}
@@ -4,27 +4,221 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type TmdbConstants struct {
-	baseURL         string
-	apiVersion      string
-	searchEndpoint  string
-	searchType      string
-	searchParameter string
-	pageParameter   string
+	baseURL            string
+	imageBaseURL       string
+	apiVersion         string
+	searchEndpoint     string
+	searchType         string
+	searchParameter    string
+	pageParameter      string
+	languageParameter  string
+	regionParameter    string
+	adultParameter     string
+	movieEndpoint      string
+	tvEndpoint         string
+	findEndpoint       string
+	authEndpoint       string
+	movieYearParameter string
+	tvYearParameter    string
+	pageSize           int
 }
 
 var tmdbConstants = TmdbConstants{
-	baseURL:         "https://api.themoviedb.org",
-	apiVersion:      "3",
-	searchEndpoint:  "search",
-	searchType:      "multi",
-	searchParameter: "query",
-	pageParameter:   "page",
+	baseURL:            "https://api.themoviedb.org",
+	imageBaseURL:       "https://image.tmdb.org/t/p/original",
+	apiVersion:         "3",
+	searchEndpoint:     "search",
+	searchType:         "multi",
+	searchParameter:    "query",
+	pageParameter:      "page",
+	languageParameter:  "language",
+	regionParameter:    "region",
+	adultParameter:     "include_adult",
+	movieEndpoint:      "movie",
+	tvEndpoint:         "tv",
+	findEndpoint:       "find",
+	authEndpoint:       "authentication",
+	movieYearParameter: "primary_release_year",
+	tvYearParameter:    "first_air_date_year",
+	pageSize:           20,
+}
+
+// tmdbFilterMaxOverfetch bounds how many results a TmdbSearcher requests, as a multiple of maxResults,
+// when the Year and Type options can't be applied by TMDB and results are filtered locally instead.
+const tmdbFilterMaxOverfetch = 4
+
+// tmdbResult is a single result in a TMDB search or list response.
+type tmdbResult struct {
+	Title            string `json:"title"`
+	Name             string `json:"name"`
+	OriginalTitle    string `json:"original_title"`
+	OriginalName     string `json:"original_name"`
+	OriginalLanguage string `json:"original_language"`
+	Adult            bool   `json:"adult"`
+	AirDate          string `json:"first_air_date"`
+	ReleaseDate      string `json:"release_date"`
+	ImdbID           string `json:"imdb_id"`
+	PosterURL        string `json:"poster_path"`
+	Type             string `json:"media_type"`
+	TmdbId           int    `json:"id"`
+}
+
+// toSearchResult converts a TMDB result to the SearchResult format.
+//
+// Parameters:
+//   - defaultType: The media type to assume if the result doesn't report one, as in single-type lists.
+//
+// Returns:
+//   - SearchResult: The converted result.
+//   - bool: Whether the result is a movie or series; TMDB also returns other types like "person".
+func (result tmdbResult) toSearchResult(defaultType string) (SearchResult, bool) {
+	var resultTitle, resultOriginalTitle string
+	if result.Title != "" {
+		resultTitle, resultOriginalTitle = result.Title, result.OriginalTitle
+	} else {
+		resultTitle, resultOriginalTitle = result.Name, result.OriginalName
+	}
+
+	mediaType := result.Type
+	if mediaType == "" {
+		mediaType = defaultType
+	}
+
+	var resultType ResultType
+	switch mediaType {
+	case "movie":
+		resultType = Movie
+	case "tv":
+		resultType = Series
+	default:
+		return SearchResult{}, false
+	}
+
+	// Results without a title can't be displayed or matched
+	if resultTitle == "" {
+		return SearchResult{}, false
+	}
+
+	resultYear := tmdbYear(result.ReleaseDate)
+	if resultYear == "" {
+		resultYear = tmdbYear(result.AirDate)
+	}
+
+	return SearchResult{
+		Title:      resultTitle,
+		Year:       resultYear,
+		ImdbID:     result.ImdbID,
+		PosterURL:  tmdbImageURL(result.PosterURL),
+		Type:       resultType,
+		ProviderId: fmt.Sprintf("%d", result.TmdbId),
+
+		OriginalTitle:    resultOriginalTitle,
+		OriginalLanguage: result.OriginalLanguage,
+		Adult:            result.Adult,
+		TmdbID:           fmt.Sprintf("%d", result.TmdbId),
+	}, true
+}
+
+// tmdbYear returns the year of a TMDB date formatted as "2006-01-02", or an empty string
+// if the date is missing or malformed.
+func tmdbYear(date string) string {
+	if len(date) < 4 {
+		return ""
+	}
+
+	for _, c := range date[:4] {
+		if c < '0' || c > '9' {
+			return ""
+		}
+	}
+
+	return date[:4]
+}
+
+// tmdbImageURL returns the full URL of a TMDB image, whose path TMDB reports relative to its image server
+// (e.g. "/f89U3ADr1oiB1s9GkdPOEpXUk5H.jpg"). Empty and already absolute paths are returned as is.
+func tmdbImageURL(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		return path
+	}
+
+	return tmdbConstants.imageBaseURL + path
+}
+
+// tmdbSearchResponse is a page of a TMDB search response.
+type tmdbSearchResponse struct {
+	Result        []tmdbResult `json:"results"`
+	TotalResults  int          `json:"total_results"`
+	TotalPages    int          `json:"total_pages"`
+	Success       bool         `json:"success"`
+	StatusMessage string       `json:"status_message"`
+}
+
+// tmdbSearchPage is a parsed page of a TMDB search response.
+type tmdbSearchPage struct {
+	// Results are the movies and series on the page; other results and malformed ones are skipped.
+	Results []SearchResult
+	// Listed is the number of results on the page, including skipped ones.
+	Listed int
+	// TotalPages is the total number of pages.
+	TotalPages int
+}
+
+// parseTmdbSearchPage parses a page of a TMDB search response. It performs no I/O, so that it can be fuzzed.
+//
+// Parameters:
+//   - statusCode: The HTTP status code of the response.
+//   - body: The body of the response.
+//   - defaultType: The media type of the results, for single-type searches whose results don't report one.
+//
+// Returns:
+//   - tmdbSearchPage: The parsed page.
+//   - error: A RateLimitError if the rate limit is exceeded, a ResultParsingError if the body is malformed,
+//     or a SearchProviderError if TMDB reports an error.
+func parseTmdbSearchPage(statusCode int, body []byte, defaultType string) (tmdbSearchPage, error) {
+	if statusCode == http.StatusTooManyRequests {
+		return tmdbSearchPage{}, NewRateLimitError("TMDB API request rate limit exceeded")
+	}
+
+	var tmdbResponse tmdbSearchResponse
+	if err := json.Unmarshal(body, &tmdbResponse); err != nil {
+		return tmdbSearchPage{}, NewResultParsingError(err.Error())
+	}
+
+	// Check for a successful response
+	if statusCode != http.StatusOK || (!tmdbResponse.Success && tmdbResponse.StatusMessage != "") {
+		return tmdbSearchPage{}, NewSearchProviderError(fmt.Sprintf("search request failed: %s", tmdbResponse.StatusMessage))
+	}
+
+	if tmdbResponse.TotalPages < 0 {
+		return tmdbSearchPage{}, NewResultParsingError(fmt.Sprintf("invalid total_pages: %d", tmdbResponse.TotalPages))
+	}
+
+	page := tmdbSearchPage{
+		Results:    make([]SearchResult, 0, len(tmdbResponse.Result)),
+		Listed:     len(tmdbResponse.Result),
+		TotalPages: tmdbResponse.TotalPages,
+	}
+
+	for _, result := range tmdbResponse.Result {
+		if searchResult, ok := result.toSearchResult(defaultType); ok {
+			page.Results = append(page.Results, searchResult)
+		}
+	}
+
+	return page, nil
 }
 
 // An TMDB-based Searcher implementation.
@@ -33,13 +227,17 @@ type TmdbSearcher struct {
 	apiKey string
 	// The HTTP client to use for making requests.
 	client *http.Client
+	// The defaults applied to every request.
+	options providerOptions
 }
 
-// NewTmdbSearcher creates a new instance of TmdbSearcher with the specified API key and client.
-func NewTmdbSearcher(apiKey string, httpClient *http.Client) *TmdbSearcher {
+// NewTmdbSearcher creates a new instance of TmdbSearcher with the specified API key, client, and options.
+// The language and region options set the defaults, which can be overridden per call using WithSearchOptions.
+func NewTmdbSearcher(apiKey string, httpClient *http.Client, opts ...Option) *TmdbSearcher {
 	return &TmdbSearcher{
-		apiKey: apiKey,
-		client: httpClient,
+		apiKey:  apiKey,
+		client:  httpClient,
+		options: newProviderOptions(opts),
 	}
 }
 
@@ -54,12 +252,29 @@ func NewTmdbSearcher(apiKey string, httpClient *http.Client) *TmdbSearcher {
 // Returns:
 //   - []SearchResult: A slice containing the search results.
 //   - error: An error if the search operation fails.
-func (os *TmdbSearcher) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+func (os *TmdbSearcher) Search(ctx context.Context, query string, maxResults int) (results []SearchResult, err error) {
+	ctx, span := os.options.tracer.Start(ctx, "TmdbSearcher.Search", trace.WithAttributes(
+		providerAttribute.String("tmdb"),
+		maxResultsAttribute.Int(maxResults),
+	))
+
+	start := time.Now()
+	defer func() {
+		os.options.metrics.ObserveSearch("tmdb", time.Since(start), len(results), err)
+		endSpan(span, len(results), err)
+	}()
+
 	if maxResults <= 0 {
 		return nil, NewInvalidMaxResultsError()
 	}
 
-	results := make([]SearchResult, 0, maxResults)
+	results = make([]SearchResult, 0, maxResults)
+
+	// Filters TMDB can't apply are applied locally, so bound the pages requested for results that may never match
+	maxPages := 0
+	if opts := os.options.resolve(ctx); opts.Type == Episode || (opts.Year != "" && opts.Type == "") {
+		maxPages = max(1, (maxResults*tmdbFilterMaxOverfetch+tmdbConstants.pageSize-1)/tmdbConstants.pageSize)
+	}
 
 	// Paginate the search results until we've accumulated maxResults or there are no more results
 	pageNumber := 1
@@ -70,7 +285,7 @@ func (os *TmdbSearcher) Search(ctx context.Context, query string, maxResults int
 			return nil, err
 		}
 
-		if !nextPageExists {
+		if !nextPageExists || (maxPages > 0 && pageNumber >= maxPages) {
 			break
 		}
 
@@ -92,9 +307,47 @@ func (os *TmdbSearcher) Search(ctx context.Context, query string, maxResults int
 // Returns:
 //   - bool: A boolean indicating whether there are more pages to retrieve.
 //   - error: An error if the search request failed or the response could not be processed.
-func (os *TmdbSearcher) searchPage(ctx context.Context, query string, maxResults int, pageNumber int, results *[]SearchResult) (bool, error) {
+func (os *TmdbSearcher) searchPage(ctx context.Context, query string, maxResults int, pageNumber int, results *[]SearchResult) (nextPage bool, err error) {
+	ctx, span := os.options.tracer.Start(ctx, "TmdbSearcher.searchPage", trace.WithAttributes(
+		providerAttribute.String("tmdb"),
+		pageAttribute.Int(pageNumber),
+	))
+
+	// The page is only recorded once the rate limiters admit its request, and timed from then on
+	var start time.Time
+	pageCount := 0
+	defer func() {
+		if !start.IsZero() {
+			os.options.metrics.ObservePage("tmdb", time.Since(start), err)
+		}
+		endSpan(span, pageCount, err)
+	}()
+
+	opts := os.options.resolve(ctx)
+
+	params := url.Values{}
+	params.Add(tmdbConstants.searchParameter, query)
+	params.Add(tmdbConstants.pageParameter, fmt.Sprintf("%d", pageNumber))
+	os.addLocalization(ctx, params)
+
+	// The multi search has no year parameter, so search a single type when the options ask for one,
+	// letting TMDB filter by year too
+	searchType, defaultType := tmdbConstants.searchType, ""
+	switch opts.Type {
+	case Movie:
+		searchType, defaultType = tmdbConstants.movieEndpoint, "movie"
+		if opts.Year != "" {
+			params.Add(tmdbConstants.movieYearParameter, opts.Year)
+		}
+	case Series:
+		searchType, defaultType = tmdbConstants.tvEndpoint, "tv"
+		if opts.Year != "" {
+			params.Add(tmdbConstants.tvYearParameter, opts.Year)
+		}
+	}
+
 	// Build the URL for the search request
-	u, err := url.JoinPath(tmdbConstants.baseURL, tmdbConstants.apiVersion, tmdbConstants.searchEndpoint, tmdbConstants.searchType)
+	u, err := url.JoinPath(os.options.baseURLOr(tmdbConstants.baseURL), tmdbConstants.apiVersion, tmdbConstants.searchEndpoint, searchType)
 	if err != nil {
 		return false, err
 	}
@@ -104,9 +357,11 @@ func (os *TmdbSearcher) searchPage(ctx context.Context, query string, maxResults
 		return false, err
 	}
 
-	params := url.Values{}
-	params.Add(tmdbConstants.searchParameter, query)
-	params.Add(tmdbConstants.pageParameter, fmt.Sprintf("%d", pageNumber))
+	safeSearch := opts.SafeSearch
+	if safeSearch {
+		params.Add(tmdbConstants.adultParameter, "false")
+	}
+
 	endpoint.RawQuery = params.Encode()
 
 	// Create the request
@@ -121,71 +376,47 @@ func (os *TmdbSearcher) searchPage(ctx context.Context, query string, maxResults
 	// Add the accept header
 	req.Header.Add("accept", "application/json")
 
+	// Propagate the trace context to the provider
+	os.options.injectTraceContext(ctx, propagation.HeaderCarrier(req.Header))
+
+	// Wait for the rate limiters to admit the request
+	if err := os.options.wait(ctx); err != nil {
+		return false, err
+	}
+
+	start = time.Now()
+
 	// Perform the request
 	resp, err := os.client.Do(req)
 	if err != nil {
-		return false, NewSearchProviderError(err.Error())
+		return false, NewSearchProviderErrorFromCause(err)
 	}
 
 	defer resp.Body.Close()
 
-	// Define the response structure
-	var tmdbResponse struct {
-		Result []struct {
-			Title       string `json:"title"`
-			Name        string `json:"name"`
-			AirDate     string `json:"first_air_date"`
-			ReleaseDate string `json:"release_date"`
-			ImdbID      string `json:"imdb_id"`
-			PosterURL   string `json:"poster_path"`
-			Type        string `json:"media_type"`
-			TmdbId      int    `json:"id"`
-		} `json:"results"`
-		TotalResults  int    `json:"total_results"`
-		TotalPages    int    `json:"total_pages"`
-		Success       bool   `json:"success"`
-		StatusMessage string `json:"status_message"`
-	}
-
-	// Decode the JSON response
-	if err := json.NewDecoder(resp.Body).Decode(&tmdbResponse); err != nil {
-		return false, NewResultParsingError(err.Error())
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, NewSearchProviderErrorFromCause(err)
 	}
 
-	// Check for a successful response
-	if resp.StatusCode != http.StatusOK || (!tmdbResponse.Success && tmdbResponse.StatusMessage != "") {
-		return false, NewSearchProviderError(fmt.Sprintf("search request failed: %s", tmdbResponse.StatusMessage))
+	page, err := parseTmdbSearchPage(resp.StatusCode, body, defaultType)
+	if err != nil {
+		return false, err
 	}
 
-	log.Printf("Found %d results for query \"%s\" on page %d\n", len(tmdbResponse.Result), query, pageNumber)
+	pageCount = len(page.Results)
 
-	// Converts the response to the SearchResult format
-	for _, result := range tmdbResponse.Result {
-		var resultTitle string
-		if result.Title != "" {
-			resultTitle = result.Title
-		} else {
-			resultTitle = result.Name
-		}
+	log.Printf("Found %d results for query \"%s\" on page %d\n", len(page.Results), query, pageNumber)
 
-		var resultType ResultType
-		switch result.Type {
-		case "movie":
-			resultType = Movie
-		case "tv":
-			resultType = Series
-		default:
-			// TMDB returns other types like "person"
+	// Filter the results
+	for _, searchResult := range page.Results {
+		if safeSearch && searchResult.Adult {
 			continue
 		}
 
-		var resultYear string
-		if result.ReleaseDate != "" {
-			resultYear = result.ReleaseDate[:4]
-		} else if result.AirDate != "" {
-			resultYear = result.AirDate[:4]
-		} else {
-			log.Println("No release date found for result:", result)
+		// Single-type searches are filtered by TMDB, but the options are checked again in case it didn't
+		if !opts.matches(searchResult) {
+			continue
 		}
 
 		maxResults--
@@ -193,20 +424,31 @@ func (os *TmdbSearcher) searchPage(ctx context.Context, query string, maxResults
 			break
 		}
 
-		*results = append(*results, SearchResult{
-			Title:      resultTitle,
-			Year:       resultYear,
-			ImdbID:     result.ImdbID,
-			PosterURL:  result.PosterURL,
-			Type:       resultType,
-			ProviderId: fmt.Sprintf("%d", result.TmdbId),
-		})
+		*results = append(*results, searchResult)
 	}
 
-	if pageNumber < tmdbResponse.TotalPages && maxResults > 0 {
+	// An empty page means the total is stale, so stop rather than request pages up to it
+	if pageNumber < page.TotalPages && maxResults > 0 && page.Listed > 0 {
 		return true, nil
 	}
 
 	// Otherwise, stop paginating
 	return false, nil
 }
+
+// addLocalization adds the effective language and region parameters, if any, to the request parameters.
+//
+// Parameters:
+//   - ctx: The context carrying any per-call SearchOptions.
+//   - params: The request parameters to add to.
+func (os *TmdbSearcher) addLocalization(ctx context.Context, params url.Values) {
+	opts := os.options.resolve(ctx)
+
+	if opts.Language != "" {
+		params.Add(tmdbConstants.languageParameter, opts.Language)
+	}
+
+	if opts.Region != "" {
+		params.Add(tmdbConstants.regionParameter, opts.Region)
+	}
+}
@@ -103,7 +103,12 @@ func TestTmdbSearcher_Search_Success(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if len(results) != 5 {
-		t.Errorf("expected 5 results, got %d", len(results))
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+
+	// Poster paths are resolved against TMDB's image server
+	if want := "https://image.tmdb.org/t/p/original/6FfCtAuVAW8XJjZ7eWeLibRLWTw.jpg"; results[0].PosterURL != want {
+		t.Errorf("expected poster URL %q, got %q", want, results[0].PosterURL)
 	}
 }
 
@@ -257,3 +262,313 @@ func TestTmdbSearcher_Search_SearchProviderError(t *testing.T) {
 		t.Fatalf("expected search provider error, got %v", err)
 	}
 }
+
+func TestTmdbSearcher_Search_OriginalTitle(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Star Wars"
+	mockData, err := loadMockResponse("tmdb_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/multi").
+		Get("/").
+		MatchParam("query", query).
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	results, err := searcher.Search(context.Background(), query, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, result := range results {
+		if result.OriginalTitle != result.Title || result.OriginalLanguage != "en" {
+			t.Errorf("expected original title %q in en, got %q in %q", result.Title, result.OriginalTitle, result.OriginalLanguage)
+		}
+	}
+}
+
+func TestTmdbSearcher_Search_DefaultLanguageAndRegion(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Star Wars"
+	mockData, err := loadMockResponse("tmdb_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/multi").
+		Get("/").
+		MatchParam("query", query).
+		MatchParam("language", "de-DE").
+		MatchParam("region", "DE").
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient, search.WithLanguage("de-DE"), search.WithRegion("DE"))
+	if _, err := searcher.Search(context.Background(), query, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !gock.IsDone() {
+		t.Error("expected localized request to be made")
+	}
+}
+
+func TestTmdbSearcher_Search_LanguageOverride(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Star Wars"
+	mockData, err := loadMockResponse("tmdb_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/multi").
+		Get("/").
+		MatchParam("query", query).
+		MatchParam("language", "ja-JP").
+		MatchParam("region", "DE").
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient, search.WithLanguage("de-DE"), search.WithRegion("DE"))
+	ctx := search.WithSearchOptions(context.Background(), search.SearchOptions{Language: "ja-JP"})
+	if _, err := searcher.Search(ctx, query, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !gock.IsDone() {
+		t.Error("expected overridden language to be sent")
+	}
+}
+
+func TestTmdbSearcher_Search_YearAndTypeFilters(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Star Wars"
+	mockData, err := loadMockResponse("tmdb_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	// The filters are sent to TMDB's movie search, and applied again locally
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/movie").
+		Get("/").
+		MatchParam("query", query).
+		MatchParam("primary_release_year", "1977").
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	ctx := search.WithSearchOptions(context.Background(), search.SearchOptions{Year: "1977", Type: search.Movie})
+	results, err := searcher.Search(ctx, query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Star Wars" {
+		t.Errorf("expected only the 1977 movie, got %+v", results)
+	}
+}
+
+func TestTmdbSearcher_Search_MalformedReleaseDate(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Matrix"
+	serverResponse := `{
+        "page": 1,
+        "results": [
+            {"id": 1, "title": "Short Date", "media_type": "movie", "release_date": "19"},
+            {"id": 2, "name": "Garbage Date", "media_type": "tv", "first_air_date": "soon"},
+            {"id": 3, "media_type": "movie", "release_date": "1999-03-30"},
+            {"id": 4, "title": "The Matrix", "media_type": "movie", "release_date": "1999-03-30"}
+        ],
+        "total_results": 4,
+        "total_pages": 1
+    }`
+
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/multi").
+		Get("/").
+		MatchParam("query", query).
+		Reply(200).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	results, err := searcher.Search(context.Background(), query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Malformed dates leave the year unknown, and results without a title are skipped
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	if results[0].Year != "" || results[1].Year != "" || results[2].Year != "1999" {
+		t.Errorf("expected years \"\", \"\", and \"1999\", got %q, %q, and %q", results[0].Year, results[1].Year, results[2].Year)
+	}
+}
+
+func TestTmdbSearcher_Search_EmptyPage(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Matrix"
+	serverResponse := `{
+        "page": 1,
+        "results": [],
+        "total_results": 10000,
+        "total_pages": 500
+    }`
+
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/multi").
+		Get("/").
+		MatchParam("query", query).
+		MatchParam("page", "1").
+		Reply(200).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	results, err := searcher.Search(context.Background(), query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %d", len(results))
+	}
+
+	if !gock.IsDone() {
+		t.Error("expected the first page to be requested")
+	}
+}
+
+func TestTmdbSearcher_Search_PeopleOnlyPage(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Wachowski"
+
+	// A page of people holds no titles, but isn't the end of the results
+	firstPage := `{
+        "page": 1,
+        "results": [
+            {"id": 9339, "name": "Lana Wachowski", "media_type": "person"},
+            {"id": 9340, "name": "Lilly Wachowski", "media_type": "person"}
+        ],
+        "total_results": 3,
+        "total_pages": 2
+    }`
+
+	secondPage := `{
+        "page": 2,
+        "results": [
+            {"id": 603, "title": "The Matrix", "release_date": "1999-03-31", "media_type": "movie"}
+        ],
+        "total_results": 3,
+        "total_pages": 2
+    }`
+
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/multi").
+		Get("/").
+		MatchParam("query", query).
+		MatchParam("page", "1").
+		Reply(200).
+		JSON(json.RawMessage(firstPage))
+
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/multi").
+		Get("/").
+		MatchParam("query", query).
+		MatchParam("page", "2").
+		Reply(200).
+		JSON(json.RawMessage(secondPage))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	results, err := searcher.Search(context.Background(), query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Title != "The Matrix" {
+		t.Errorf("expected The Matrix from the second page, got %+v", results)
+	}
+
+	if !gock.IsDone() {
+		t.Error("expected both pages to be requested")
+	}
+}
+
+func TestTmdbSearcher_Search_SeriesYearFilter(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "The Office"
+	serverResponse := `{
+        "page": 1,
+        "results": [
+            {"id": 2316, "name": "The Office", "first_air_date": "2005-03-24"}
+        ],
+        "total_results": 1,
+        "total_pages": 1
+    }`
+
+	// TV search results don't report their media type
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/tv").
+		Get("/").
+		MatchParam("query", query).
+		MatchParam("first_air_date_year", "2005").
+		Reply(200).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	ctx := search.WithSearchOptions(context.Background(), search.SearchOptions{Year: "2005", Type: search.Series})
+	results, err := searcher.Search(ctx, query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Type != search.Series || results[0].TmdbID != "2316" {
+		t.Errorf("expected the 2005 series, got %+v", results)
+	}
+}
+
+func TestTmdbSearcher_Search_LocalFilterPageCap(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Star Wars"
+	serverResponse := `{
+        "results": [
+            {"id": 1, "title": "Star Wars", "media_type": "movie", "release_date": "1977-05-25"}
+        ],
+        "total_results": 10000,
+        "total_pages": 500
+    }`
+
+	// A year without a type can only be filtered locally, so pages are requested until the cap:
+	// 5 results, overfetched 4 times, fit on a single page of 20
+	gock.New("https://api.themoviedb.org").
+		Path("/3/search/multi").
+		Get("/").
+		MatchParam("query", query).
+		Times(2).
+		Reply(200).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	ctx := search.WithSearchOptions(context.Background(), search.SearchOptions{Year: "2020"})
+	results, err := searcher.Search(ctx, query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %+v", results)
+	}
+	if pending := len(gock.Pending()); pending != 1 {
+		t.Errorf("expected a single page to be requested, got %d pending mocks", pending)
+	}
+}
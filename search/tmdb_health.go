@@ -0,0 +1,67 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// CheckHealth probes TMDB by validating the API key, which costs a single request.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//
+// Returns:
+//   - HealthStatus: The health of TMDB.
+//   - error: An InvalidAPIKeyError, a RateLimitError, or a SearchProviderError if TMDB isn't healthy,
+//     or the context error if ctx is done while waiting for the rate limiters.
+func (os *TmdbSearcher) CheckHealth(ctx context.Context) (HealthStatus, error) {
+	u, err := url.JoinPath(os.options.baseURLOr(tmdbConstants.baseURL), tmdbConstants.apiVersion, tmdbConstants.authEndpoint)
+	if err != nil {
+		return HealthUnreachable, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return HealthUnreachable, err
+	}
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", os.apiKey))
+	req.Header.Add("accept", "application/json")
+	os.options.injectTraceContext(ctx, propagation.HeaderCarrier(req.Header))
+
+	// Wait for the rate limiters to admit the probe
+	if status, err := os.options.waitForProbe(ctx); err != nil {
+		return status, err
+	}
+
+	resp, err := os.client.Do(req)
+	if err != nil {
+		return HealthUnreachable, NewSearchProviderErrorFromCause(err)
+	}
+
+	defer resp.Body.Close()
+
+	var tmdbResponse struct {
+		Success       bool   `json:"success"`
+		StatusCode    int    `json:"status_code"`
+		StatusMessage string `json:"status_message"`
+	}
+	// The body is informational only, so a decoding failure is not fatal
+	_ = json.NewDecoder(resp.Body).Decode(&tmdbResponse)
+
+	switch {
+	case resp.StatusCode == http.StatusOK && tmdbResponse.Success:
+		return Healthy, nil
+	case resp.StatusCode == http.StatusUnauthorized:
+		return HealthInvalidKey, NewInvalidAPIKeyError("TMDB", tmdbResponse.StatusMessage)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return HealthQuotaExhausted, NewRateLimitError("TMDB API request rate limit exceeded")
+	default:
+		return HealthUnreachable, NewSearchProviderError(fmt.Sprintf("health check failed with status %d: %s", resp.StatusCode, tmdbResponse.StatusMessage))
+	}
+}
@@ -0,0 +1,299 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// tmdbCertificationCountry is the country whose certification is reported in TitleDetails.Rated.
+const tmdbCertificationCountry = "US"
+
+// tmdbNamedEntity is the shape shared by TMDB genres, companies, and people.
+type tmdbNamedEntity struct {
+	Name string `json:"name"`
+}
+
+// tmdbDetailsResponse is the union of the movie and TV details responses, including appended credits and IDs.
+type tmdbDetailsResponse struct {
+	TmdbId           int               `json:"id"`
+	Title            string            `json:"title"`
+	Name             string            `json:"name"`
+	OriginalTitle    string            `json:"original_title"`
+	OriginalName     string            `json:"original_name"`
+	OriginalLanguage string            `json:"original_language"`
+	Adult            bool              `json:"adult"`
+	ReleaseDate      string            `json:"release_date"`
+	AirDate          string            `json:"first_air_date"`
+	Overview         string            `json:"overview"`
+	PosterURL        string            `json:"poster_path"`
+	ImdbID           string            `json:"imdb_id"`
+	Runtime          int               `json:"runtime"`
+	EpisodeRuntime   []int             `json:"episode_run_time"`
+	Genres           []tmdbNamedEntity `json:"genres"`
+	CreatedBy        []tmdbNamedEntity `json:"created_by"`
+	SpokenLanguages  []struct {
+		EnglishName string `json:"english_name"`
+	} `json:"spoken_languages"`
+	ProductionCountries []tmdbNamedEntity `json:"production_countries"`
+	VoteAverage         float64           `json:"vote_average"`
+	VoteCount           int               `json:"vote_count"`
+	Credits             struct {
+		Cast []tmdbNamedEntity `json:"cast"`
+		Crew []struct {
+			Name string `json:"name"`
+			Job  string `json:"job"`
+		} `json:"crew"`
+	} `json:"credits"`
+	ExternalIDs struct {
+		ImdbID string `json:"imdb_id"`
+	} `json:"external_ids"`
+	ReleaseDates struct {
+		Results []struct {
+			Country      string `json:"iso_3166_1"`
+			ReleaseDates []struct {
+				Certification string `json:"certification"`
+			} `json:"release_dates"`
+		} `json:"results"`
+	} `json:"release_dates"`
+	ContentRatings struct {
+		Results []struct {
+			Country string `json:"iso_3166_1"`
+			Rating  string `json:"rating"`
+		} `json:"results"`
+	} `json:"content_ratings"`
+}
+
+// LookupByImdbID retrieves the details of the title with the given IMDb ID.
+// The IMDb ID is first resolved to a TMDB ID using the find endpoint.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - imdbID: The IMDb ID of the title.
+//
+// Returns:
+//   - *TitleDetails: The details of the matching title.
+//   - error: A TitleNotFoundError if no title matches, or another error if the lookup fails.
+func (os *TmdbSearcher) LookupByImdbID(ctx context.Context, imdbID string) (*TitleDetails, error) {
+	params := url.Values{}
+	params.Add("external_source", "imdb_id")
+
+	var findResponse struct {
+		MovieResults []struct {
+			TmdbId int `json:"id"`
+		} `json:"movie_results"`
+		TvResults []struct {
+			TmdbId int `json:"id"`
+		} `json:"tv_results"`
+	}
+
+	if err := os.get(ctx, params, &findResponse, tmdbConstants.findEndpoint, imdbID); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(findResponse.MovieResults) > 0:
+		return os.LookupByProviderID(ctx, fmt.Sprintf("%d", findResponse.MovieResults[0].TmdbId), Movie)
+	case len(findResponse.TvResults) > 0:
+		return os.LookupByProviderID(ctx, fmt.Sprintf("%d", findResponse.TvResults[0].TmdbId), Series)
+	default:
+		return nil, NewTitleNotFoundError(imdbID)
+	}
+}
+
+// LookupByProviderID retrieves the details of the title with the given TMDB ID.
+// TMDB namespaces IDs by type, so resultType must be either Movie or Series.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - providerID: The TMDB ID of the title.
+//   - resultType: The type of the title (Movie or Series).
+//
+// Returns:
+//   - *TitleDetails: The details of the matching title.
+//   - error: A TitleNotFoundError if no title matches, or another error if the lookup fails.
+func (os *TmdbSearcher) LookupByProviderID(ctx context.Context, providerID string, resultType ResultType) (*TitleDetails, error) {
+	var endpoint, appended string
+	switch resultType {
+	case Movie:
+		endpoint, appended = tmdbConstants.movieEndpoint, "credits,external_ids,release_dates"
+	case Series:
+		endpoint, appended = tmdbConstants.tvEndpoint, "credits,external_ids,content_ratings"
+	default:
+		return nil, NewSearchProviderError(fmt.Sprintf("unsupported result type for TMDB lookup: %q", resultType))
+	}
+
+	params := url.Values{}
+	params.Add("append_to_response", appended)
+	os.addLocalization(ctx, params)
+
+	var tmdbResponse tmdbDetailsResponse
+	if err := os.get(ctx, params, &tmdbResponse, endpoint, providerID); err != nil {
+		return nil, err
+	}
+
+	return tmdbResponse.toTitleDetails(resultType), nil
+}
+
+// get performs an authenticated GET request against the given TMDB API path and decodes the response into out.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - params: The query parameters to send.
+//   - out: A pointer to the value the JSON response is decoded into.
+//   - path: The path elements following the API version.
+//
+// Returns:
+//   - error: A TitleNotFoundError if the resource does not exist, or another error if the request failed.
+func (os *TmdbSearcher) get(ctx context.Context, params url.Values, out any, path ...string) error {
+	// Build the URL for the request
+	u, err := url.JoinPath(os.options.baseURLOr(tmdbConstants.baseURL), append([]string{tmdbConstants.apiVersion}, path...)...)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+
+	endpoint.RawQuery = params.Encode()
+
+	// Create the request
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", os.apiKey))
+	req.Header.Add("accept", "application/json")
+
+	// Wait for the rate limiters to admit the request
+	if err := os.options.wait(ctx); err != nil {
+		return err
+	}
+
+	// Perform the request
+	resp, err := os.client.Do(req)
+	if err != nil {
+		return NewSearchProviderErrorFromCause(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return NewRateLimitError("TMDB API request rate limit exceeded")
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return NewTitleNotFoundError(path[len(path)-1])
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var tmdbError struct {
+			StatusMessage string `json:"status_message"`
+		}
+		// The error body is informational only, so a decoding failure is not fatal
+		_ = json.NewDecoder(resp.Body).Decode(&tmdbError)
+		return NewSearchProviderError(fmt.Sprintf("request failed: %s", tmdbError.StatusMessage))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return NewResultParsingError(err.Error())
+	}
+
+	return nil
+}
+
+// toTitleDetails converts a TMDB details response to the TitleDetails format.
+func (r *tmdbDetailsResponse) toTitleDetails(resultType ResultType) *TitleDetails {
+	details := &TitleDetails{
+		SearchResult: SearchResult{
+			Title:      r.Title,
+			ImdbID:     r.ImdbID,
+			PosterURL:  tmdbImageURL(r.PosterURL),
+			Type:       resultType,
+			ProviderId: fmt.Sprintf("%d", r.TmdbId),
+		},
+		Plot:    r.Overview,
+		Runtime: r.Runtime,
+	}
+
+	details.OriginalTitle, details.OriginalLanguage = r.OriginalTitle, r.OriginalLanguage
+	details.Adult = r.Adult
+	details.TmdbID = details.ProviderId
+
+	if details.Title == "" {
+		details.Title, details.OriginalTitle = r.Name, r.OriginalName
+	}
+
+	if details.ImdbID == "" {
+		details.ImdbID = r.ExternalIDs.ImdbID
+	}
+
+	details.Year = tmdbYear(r.ReleaseDate)
+	if details.Year == "" {
+		details.Year = tmdbYear(r.AirDate)
+	}
+
+	if details.Runtime == 0 && len(r.EpisodeRuntime) > 0 {
+		details.Runtime = r.EpisodeRuntime[0]
+	}
+
+	for _, genre := range r.Genres {
+		details.Genres = append(details.Genres, genre.Name)
+	}
+
+	for _, member := range r.Credits.Cast {
+		details.Cast = append(details.Cast, member.Name)
+	}
+
+	// Series are credited to their creators rather than to a single director
+	for _, creator := range r.CreatedBy {
+		details.Directors = append(details.Directors, creator.Name)
+	}
+
+	for _, member := range r.Credits.Crew {
+		if member.Job == "Director" {
+			details.Directors = append(details.Directors, member.Name)
+		}
+	}
+
+	for _, language := range r.SpokenLanguages {
+		details.Languages = append(details.Languages, language.EnglishName)
+	}
+
+	for _, country := range r.ProductionCountries {
+		details.Countries = append(details.Countries, country.Name)
+	}
+
+	if r.VoteCount > 0 {
+		details.Ratings = append(details.Ratings, Rating{
+			Source: "The Movie Database",
+			Value:  fmt.Sprintf("%.1f/10", r.VoteAverage),
+		})
+	}
+
+	for _, release := range r.ReleaseDates.Results {
+		if release.Country != tmdbCertificationCountry {
+			continue
+		}
+
+		for _, date := range release.ReleaseDates {
+			if date.Certification != "" {
+				details.Rated = date.Certification
+				break
+			}
+		}
+	}
+
+	for _, rating := range r.ContentRatings.Results {
+		if rating.Country == tmdbCertificationCountry {
+			details.Rated = rating.Rating
+		}
+	}
+
+	return details
+}
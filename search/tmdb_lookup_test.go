@@ -0,0 +1,171 @@
+package search_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/jdahan/gogettitles/search"
+)
+
+func TestTmdbSearcher_LookupByProviderID_Movie(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	mockData, err := loadMockResponse("tmdb_movie_details_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://api.themoviedb.org").
+		Get("/3/movie/603").
+		MatchParam("append_to_response", "credits").
+		MatchHeader("Authorization", "Bearer "+testAPIKey).
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	details, err := searcher.LookupByProviderID(context.Background(), "603", search.Movie)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if details.Title != "The Matrix" || details.Year != "1999" || details.ImdbID != "tt0133093" || details.ProviderId != "603" || details.TmdbID != "603" {
+		t.Errorf("unexpected result: %+v", details.SearchResult)
+	}
+	if want := "https://image.tmdb.org/t/p/original/p96dm7sCMn4VYAStA6siNz30G1r.jpg"; details.PosterURL != want {
+		t.Errorf("expected poster URL %q, got %q", want, details.PosterURL)
+	}
+	if details.Runtime != 136 || details.Rated != "R" {
+		t.Errorf("expected runtime 136 rated R, got %d rated %q", details.Runtime, details.Rated)
+	}
+	if want := []string{"Lilly Wachowski", "Lana Wachowski"}; !reflect.DeepEqual(details.Directors, want) {
+		t.Errorf("expected directors %v, got %v", want, details.Directors)
+	}
+	if want := []string{"Action", "Science Fiction"}; !reflect.DeepEqual(details.Genres, want) {
+		t.Errorf("expected genres %v, got %v", want, details.Genres)
+	}
+	if len(details.Cast) != 3 || len(details.Ratings) != 1 || len(details.Languages) != 1 || len(details.Countries) != 1 {
+		t.Errorf("unexpected details: %+v", details)
+	}
+}
+
+func TestTmdbSearcher_LookupByProviderID_Series(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	mockData, err := loadMockResponse("tmdb_tv_details_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://api.themoviedb.org").
+		Get("/3/tv/1396").
+		MatchHeader("Authorization", "Bearer "+testAPIKey).
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	details, err := searcher.LookupByProviderID(context.Background(), "1396", search.Series)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if details.Title != "Breaking Bad" || details.Year != "2008" || details.ImdbID != "tt0903747" || details.Type != search.Series {
+		t.Errorf("unexpected result: %+v", details.SearchResult)
+	}
+	if details.Runtime != 45 || details.Rated != "TV-MA" {
+		t.Errorf("expected runtime 45 rated TV-MA, got %d rated %q", details.Runtime, details.Rated)
+	}
+	if want := []string{"Vince Gilligan"}; !reflect.DeepEqual(details.Directors, want) {
+		t.Errorf("expected directors %v, got %v", want, details.Directors)
+	}
+}
+
+func TestTmdbSearcher_LookupByProviderID_UnsupportedType(t *testing.T) {
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	_, err := searcher.LookupByProviderID(context.Background(), "1", search.Episode)
+	var spErr *search.SearchProviderError
+	if err == nil || !errors.As(err, &spErr) {
+		t.Fatalf("expected search provider error, got %v", err)
+	}
+}
+
+func TestTmdbSearcher_LookupByProviderID_NotFound(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	serverResponse := `{
+		"success":false,
+		"status_code":34,
+		"status_message":"The resource you requested could not be found."
+	}`
+
+	gock.New("https://api.themoviedb.org").
+		Get("/3/movie/0").
+		Reply(404).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	_, err := searcher.LookupByProviderID(context.Background(), "0", search.Movie)
+	var nfErr *search.TitleNotFoundError
+	if err == nil || !errors.As(err, &nfErr) {
+		t.Fatalf("expected title not found error, got %v", err)
+	}
+}
+
+func TestTmdbSearcher_LookupByImdbID_Success(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	findData, err := loadMockResponse("tmdb_find_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	detailsData, err := loadMockResponse("tmdb_movie_details_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://api.themoviedb.org").
+		Get("/3/find/tt0133093").
+		MatchParam("external_source", "imdb_id").
+		Reply(200).
+		JSON(json.RawMessage(findData))
+
+	gock.New("https://api.themoviedb.org").
+		Get("/3/movie/603").
+		Reply(200).
+		JSON(json.RawMessage(detailsData))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	details, err := searcher.LookupByImdbID(context.Background(), "tt0133093")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if details.ProviderId != "603" || details.Title != "The Matrix" {
+		t.Errorf("unexpected result: %+v", details.SearchResult)
+	}
+}
+
+func TestTmdbSearcher_LookupByImdbID_NotFound(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	serverResponse := `{
+		"movie_results": [],
+		"tv_results": []
+	}`
+
+	gock.New("https://api.themoviedb.org").
+		Get("/3/find/tt0000000").
+		Reply(200).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient)
+	_, err := searcher.LookupByImdbID(context.Background(), "tt0000000")
+	var nfErr *search.TitleNotFoundError
+	if err == nil || !errors.As(err, &nfErr) {
+		t.Fatalf("expected title not found error, got %v", err)
+	}
+}
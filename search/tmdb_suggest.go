@@ -0,0 +1,113 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// A TmdbSuggestionList is a TMDB list of titles that can be used as suggestions.
+type TmdbSuggestionList string
+
+const (
+	// TmdbTrendingToday lists the movies and series trending today.
+	TmdbTrendingToday TmdbSuggestionList = "trending/all/day"
+	// TmdbTrendingThisWeek lists the movies and series trending this week.
+	TmdbTrendingThisWeek TmdbSuggestionList = "trending/all/week"
+	// TmdbPopularMovies lists the most popular movies.
+	TmdbPopularMovies TmdbSuggestionList = "movie/popular"
+	// TmdbPopularSeries lists the most popular series.
+	TmdbPopularSeries TmdbSuggestionList = "tv/popular"
+)
+
+// A TMDB-based Suggester implementation, suggesting the titles in a TMDB list.
+type TmdbSuggester struct {
+	// The searcher whose API key, client, and options are used.
+	searcher *TmdbSearcher
+	// The list to suggest titles from.
+	list TmdbSuggestionList
+}
+
+// NewTmdbSuggester creates a new instance of TmdbSuggester.
+//
+// Parameters:
+//   - searcher: The TmdbSearcher whose API key, client, and options are used.
+//   - list: The TMDB list to suggest titles from.
+//
+// Returns:
+//   - *TmdbSuggester: A new instance of TmdbSuggester.
+func NewTmdbSuggester(searcher *TmdbSearcher, list TmdbSuggestionList) *TmdbSuggester {
+	return &TmdbSuggester{
+		searcher: searcher,
+		list:     list,
+	}
+}
+
+// Suggest returns the titles in the list, in the order TMDB ranks them.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - maxResults: The maximum number of suggestions to return.
+//
+// Returns:
+//   - []SearchResult: A slice containing the suggestions.
+//   - error: An error if the list could not be retrieved.
+func (ts *TmdbSuggester) Suggest(ctx context.Context, maxResults int) ([]SearchResult, error) {
+	if maxResults <= 0 {
+		return nil, NewInvalidMaxResultsError()
+	}
+
+	// Single-type lists don't report the media type of their results
+	var defaultType string
+	switch ts.list {
+	case TmdbPopularMovies:
+		defaultType = "movie"
+	case TmdbPopularSeries:
+		defaultType = "tv"
+	}
+
+	safeSearch := ts.searcher.options.resolve(ctx).SafeSearch
+	results := make([]SearchResult, 0, maxResults)
+
+	// People and adult titles are skipped, so bound the results listed for suggestions that may never match
+	maxListed := maxResults * tmdbFilterMaxOverfetch
+	listed := 0
+
+	for pageNumber := 1; len(results) < maxResults; pageNumber++ {
+		params := url.Values{}
+		params.Add(tmdbConstants.pageParameter, fmt.Sprintf("%d", pageNumber))
+		ts.searcher.addLocalization(ctx, params)
+
+		var tmdbResponse struct {
+			Result     []tmdbResult `json:"results"`
+			TotalPages int          `json:"total_pages"`
+		}
+
+		if err := ts.searcher.get(ctx, params, &tmdbResponse, strings.Split(string(ts.list), "/")...); err != nil {
+			return nil, err
+		}
+
+		for _, result := range tmdbResponse.Result {
+			searchResult, ok := result.toSearchResult(defaultType)
+			if !ok || (safeSearch && result.Adult) {
+				continue
+			}
+
+			if len(results) == maxResults {
+				break
+			}
+
+			results = append(results, searchResult)
+		}
+
+		listed += len(tmdbResponse.Result)
+
+		// An empty page means the total is stale, so stop rather than request pages up to it
+		if pageNumber >= tmdbResponse.TotalPages || listed >= maxListed || len(tmdbResponse.Result) == 0 {
+			break
+		}
+	}
+
+	return results, nil
+}
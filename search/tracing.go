@@ -0,0 +1,83 @@
+package search
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the OpenTelemetry tracer searches are traced with.
+const tracerName = "github.com/jdahan/gogettitles/search"
+
+// The attributes recorded on search spans.
+const (
+	// providerAttribute is the provider searched, e.g. "tmdb".
+	providerAttribute = attribute.Key("search.provider")
+	// maxResultsAttribute is the maximum number of results requested.
+	maxResultsAttribute = attribute.Key("search.max_results")
+	// pageAttribute is the 1-based page requested from the provider.
+	pageAttribute = attribute.Key("search.page")
+	// resultCountAttribute is the number of results returned.
+	resultCountAttribute = attribute.Key("search.result_count")
+	// cacheHitAttribute is whether the results were served from the cache.
+	cacheHitAttribute = attribute.Key("search.cache_hit")
+)
+
+// WithTracerProvider sets the OpenTelemetry TracerProvider searches are traced with. Each Search call is traced
+// as a span, with a child span per page requested from the provider.
+// Defaults to the global TracerProvider, which doesn't record anything unless one is registered with otel.SetTracerProvider.
+// A nil provider also selects the global TracerProvider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *providerOptions) {
+		o.tracer = tracerOrDefault(provider)
+	}
+}
+
+// WithPropagator sets the OpenTelemetry propagator used to propagate the trace context into the provider's
+// HTTP requests. Defaults to the global propagator, which doesn't propagate anything unless one is registered
+// with otel.SetTextMapPropagator.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(o *providerOptions) {
+		o.propagator = propagator
+	}
+}
+
+// tracerOrDefault returns the tracer from the provider, or from the global TracerProvider if provider is nil.
+func tracerOrDefault(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return provider.Tracer(tracerName)
+}
+
+// injectTraceContext adds the trace context of ctx to the headers of an outgoing request.
+func (o providerOptions) injectTraceContext(ctx context.Context, header propagation.HeaderCarrier) {
+	propagator := o.propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+
+	propagator.Inject(ctx, header)
+}
+
+// endSpan records the outcome of an operation on its span and ends it.
+//
+// Parameters:
+//   - span: The span of the operation.
+//   - resultCount: The number of results the operation returned.
+//   - err: The error the operation failed with, if any.
+func endSpan(span trace.Span, resultCount int, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(resultCountAttribute.Int(resultCount))
+	}
+
+	span.End()
+}
@@ -0,0 +1,199 @@
+package search_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/jdahan/gogettitles/search"
+	"github.com/jdahan/gogettitles/search/searchtest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTestTracerProvider returns a TracerProvider exporting spans synchronously to an in-memory exporter.
+func newTestTracerProvider(t *testing.T) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	return provider, exporter
+}
+
+// spanAttribute returns the value of an attribute of a span, and whether the span has it.
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func TestTmdbSearcher_Search_Tracing(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	provider, exporter := newTestTracerProvider(t)
+	query := "Star Wars"
+
+	for page, file := range []string{"tmdb_paginated_response_1.json", "tmdb_paginated_response_2.json"} {
+		mockData, err := loadMockResponse(file)
+		if err != nil {
+			t.Fatalf("unexpected error reading test data: %v", err)
+		}
+
+		gock.New("https://api.themoviedb.org").
+			Path("/3/search/multi").
+			Get("/").
+			MatchParam("page", []string{"1", "2"}[page]).
+			MatchParam("query", query).
+			MatchHeader("traceparent", "^00-[0-9a-f]{32}-[0-9a-f]{16}-01$").
+			Reply(200).
+			JSON(json.RawMessage(mockData))
+	}
+
+	searcher := search.NewTmdbSearcher(testAPIKey, http.DefaultClient,
+		search.WithTracerProvider(provider),
+		search.WithPropagator(propagation.TraceContext{}))
+
+	// The first page holds 5 results, so 7 results span both pages
+	results, err := searcher.Search(context.Background(), query, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	// Child spans end, and are exported, before their parent
+	root := spans[2]
+	if root.Name != "TmdbSearcher.Search" {
+		t.Fatalf("expected root span TmdbSearcher.Search, got %s", root.Name)
+	}
+
+	if value, _ := spanAttribute(root, "search.provider"); value.AsString() != "tmdb" {
+		t.Errorf("expected provider tmdb, got %q", value.AsString())
+	}
+
+	if value, _ := spanAttribute(root, "search.result_count"); value.AsInt64() != int64(len(results)) {
+		t.Errorf("expected result count %d, got %d", len(results), value.AsInt64())
+	}
+
+	for i, span := range spans[:2] {
+		if span.Name != "TmdbSearcher.searchPage" {
+			t.Errorf("expected page span TmdbSearcher.searchPage, got %s", span.Name)
+		}
+
+		if span.Parent.SpanID() != root.SpanContext.SpanID() {
+			t.Errorf("expected page span %d to be a child of the search span", i+1)
+		}
+
+		if value, _ := spanAttribute(span, "search.page"); value.AsInt64() != int64(i+1) {
+			t.Errorf("expected page %d, got %d", i+1, value.AsInt64())
+		}
+
+		if _, ok := spanAttribute(span, "search.result_count"); !ok {
+			t.Errorf("expected page span %d to record its result count", i+1)
+		}
+	}
+
+	if !gock.IsDone() {
+		t.Error("expected the trace context to be propagated to every page request")
+	}
+}
+
+func TestOmdbSearcher_Search_TracingError(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	provider, exporter := newTestTracerProvider(t)
+	query := "Test"
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("apiKey", testAPIKey).
+		MatchParam("s", query).
+		Reply(401).
+		JSON(map[string]string{"Response": "False", "Error": "Invalid API key!"})
+
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient, search.WithTracerProvider(provider))
+	if _, err := searcher.Search(context.Background(), query, 5); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	for _, span := range spans {
+		if span.Status.Code != codes.Error {
+			t.Errorf("expected span %s to have error status, got %v", span.Name, span.Status.Code)
+		}
+
+		if len(span.Events) == 0 || span.Events[0].Name != "exception" {
+			t.Errorf("expected span %s to record the error", span.Name)
+		}
+
+		if value, _ := spanAttribute(span, "search.provider"); value.AsString() != "omdb" {
+			t.Errorf("expected provider omdb, got %q", value.AsString())
+		}
+	}
+}
+
+func TestOmdbSearcher_Search_NilTracerProvider(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	mockData, err := loadMockResponse("omdb_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://www.omdbapi.com").
+		Get("/").
+		MatchParam("s", "Test").
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	// A nil provider falls back to the global TracerProvider instead of panicking
+	searcher := search.NewOmdbSearcher(testAPIKey, http.DefaultClient, search.WithTracerProvider(nil))
+	if _, err := searcher.Search(context.Background(), "Test", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCachingSearcher_Search_Tracing(t *testing.T) {
+	provider, exporter := newTestTracerProvider(t)
+
+	cache := search.NewCachingSearcher(searchtest.NewFakeSearcher(nil), search.CachingSearcherConfig{TracerProvider: provider})
+
+	for range 2 {
+		if _, err := cache.Search(context.Background(), "matrix", 3); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	for i, want := range []bool{false, true} {
+		if value, ok := spanAttribute(spans[i], "search.cache_hit"); !ok || value.AsBool() != want {
+			t.Errorf("expected search %d to have cache hit %v, got %v", i+1, want, value.AsBool())
+		}
+
+		if value, _ := spanAttribute(spans[i], "search.result_count"); value.AsInt64() != 3 {
+			t.Errorf("expected search %d to return 3 results, got %d", i+1, value.AsInt64())
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TraktConstants struct {
+	baseURL          string
+	searchEndpoint   string
+	searchTypes      string
+	movieType        string
+	showType         string
+	searchParameter  string
+	pageParameter    string
+	limitParameter   string
+	yearsParameter   string
+	apiKeyHeader     string
+	apiVersionHeader string
+	apiVersion       string
+	pageCountHeader  string
+	maxPageSize      int
+}
+
+var traktConstants = TraktConstants{
+	baseURL:          "https://api.trakt.tv",
+	searchEndpoint:   "search",
+	searchTypes:      "movie,show",
+	movieType:        "movie",
+	showType:         "show",
+	searchParameter:  "query",
+	pageParameter:    "page",
+	limitParameter:   "limit",
+	yearsParameter:   "years",
+	apiKeyHeader:     "trakt-api-key",
+	apiVersionHeader: "trakt-api-version",
+	apiVersion:       "2",
+	pageCountHeader:  "X-Pagination-Page-Count",
+	maxPageSize:      100,
+}
+
+// traktIDs are the identifiers of a Trakt movie or show. IDs unknown to Trakt are null.
+type traktIDs struct {
+	Trakt int    `json:"trakt"`
+	Slug  string `json:"slug"`
+	Imdb  string `json:"imdb"`
+	Tmdb  *int   `json:"tmdb"`
+}
+
+// traktMedia is a Trakt movie or show.
+type traktMedia struct {
+	Title string   `json:"title"`
+	Year  *int     `json:"year"`
+	IDs   traktIDs `json:"ids"`
+}
+
+// traktResult is a single result in a Trakt search response.
+type traktResult struct {
+	Type  string      `json:"type"`
+	Score float64     `json:"score"`
+	Movie *traktMedia `json:"movie"`
+	Show  *traktMedia `json:"show"`
+}
+
+// toSearchResult converts a Trakt result to the SearchResult format.
+//
+// Returns:
+//   - SearchResult: The converted result.
+//   - bool: Whether the result is a well-formed movie or show.
+func (result traktResult) toSearchResult() (SearchResult, bool) {
+	var media *traktMedia
+	var resultType ResultType
+	switch result.Type {
+	case traktConstants.movieType:
+		media, resultType = result.Movie, Movie
+	case traktConstants.showType:
+		media, resultType = result.Show, Series
+	default:
+		return SearchResult{}, false
+	}
+
+	// Results without a title can't be displayed or matched
+	if media == nil || media.Title == "" {
+		return SearchResult{}, false
+	}
+
+	var resultYear string
+	if media.Year != nil && *media.Year > 0 {
+		resultYear = strconv.Itoa(*media.Year)
+	}
+
+	var tmdbID string
+	if media.IDs.Tmdb != nil {
+		tmdbID = strconv.Itoa(*media.IDs.Tmdb)
+	}
+
+	return SearchResult{
+		Title:      media.Title,
+		Year:       resultYear,
+		ImdbID:     media.IDs.Imdb,
+		Type:       resultType,
+		ProviderId: strconv.Itoa(media.IDs.Trakt),
+
+		TmdbID: tmdbID,
+	}, true
+}
+
+// traktSearchPage is a parsed page of a Trakt search response.
+type traktSearchPage struct {
+	// Results are the titles on the page; results of other types and malformed ones are skipped.
+	Results []SearchResult
+	// Listed is the number of results on the page, including skipped ones.
+	Listed int
+	// PageCount is the total number of pages.
+	PageCount int
+}
+
+// parseTraktSearchPage parses a page of a Trakt search response. It performs no I/O, so that it can be fuzzed.
+//
+// Parameters:
+//   - statusCode: The HTTP status code of the response.
+//   - pageCount: The value of the X-Pagination-Page-Count header, or an empty string if it is missing.
+//   - body: The body of the response.
+//
+// Returns:
+//   - traktSearchPage: The parsed page.
+//   - error: A RateLimitError if the rate limit is exceeded, a SearchProviderError if Trakt reports an error,
+//     or a ResultParsingError if the response is malformed.
+func parseTraktSearchPage(statusCode int, pageCount string, body []byte) (traktSearchPage, error) {
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		return traktSearchPage{}, NewRateLimitError("Trakt API request rate limit exceeded")
+	case http.StatusUnauthorized, http.StatusForbidden:
+		// Trakt answers an unknown or unapproved client ID with an empty body
+		return traktSearchPage{}, NewSearchProviderError(fmt.Sprintf("search request failed with status %d: invalid client ID", statusCode))
+	default:
+		return traktSearchPage{}, NewSearchProviderError(fmt.Sprintf("search request failed with status %d", statusCode))
+	}
+
+	var traktResponse []traktResult
+	if err := json.Unmarshal(body, &traktResponse); err != nil {
+		return traktSearchPage{}, NewResultParsingError(err.Error())
+	}
+
+	// A response without pagination headers holds every result
+	totalPages := 1
+	if pageCount != "" {
+		var err error
+		if totalPages, err = strconv.Atoi(pageCount); err != nil || totalPages < 0 {
+			return traktSearchPage{}, NewResultParsingError(fmt.Sprintf("invalid page count: %q", pageCount))
+		}
+	}
+
+	page := traktSearchPage{
+		Results:   make([]SearchResult, 0, len(traktResponse)),
+		Listed:    len(traktResponse),
+		PageCount: totalPages,
+	}
+
+	for _, result := range traktResponse {
+		if searchResult, ok := result.toSearchResult(); ok {
+			page.Results = append(page.Results, searchResult)
+		}
+	}
+
+	return page, nil
+}
+
+// A Trakt-based Searcher implementation.
+type TraktSearcher struct {
+	// The Trakt client ID to use for searching.
+	clientID string
+	// The HTTP client to use for making requests.
+	client *http.Client
+	// The defaults applied to every request.
+	options providerOptions
+}
+
+// NewTraktSearcher creates a new instance of TraktSearcher with the specified client ID, client, and options.
+// Trakt has no localization or adult content, so the language, region, and safe search options are ignored.
+func NewTraktSearcher(clientID string, httpClient *http.Client, opts ...Option) *TraktSearcher {
+	return &TraktSearcher{
+		clientID: clientID,
+		client:   httpClient,
+		options:  newProviderOptions(opts),
+	}
+}
+
+// Search performs a search operation based on the provided query string.
+// It returns a slice of SearchResult and an error, if any occurs during the search.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - query: The search query string.
+//   - maxResults: The maximum number of search results to return.
+//
+// Returns:
+//   - []SearchResult: A slice containing the search results.
+//   - error: An error if the search operation fails.
+func (os *TraktSearcher) Search(ctx context.Context, query string, maxResults int) (results []SearchResult, err error) {
+	ctx, span := os.options.tracer.Start(ctx, "TraktSearcher.Search", trace.WithAttributes(
+		providerAttribute.String("trakt"),
+		maxResultsAttribute.Int(maxResults),
+	))
+
+	start := time.Now()
+	defer func() {
+		os.options.metrics.ObserveSearch("trakt", time.Since(start), len(results), err)
+		endSpan(span, len(results), err)
+	}()
+
+	if maxResults <= 0 {
+		return nil, NewInvalidMaxResultsError()
+	}
+
+	results = make([]SearchResult, 0, maxResults)
+
+	// The page size must stay the same across pages, or they would overlap
+	pageSize := min(maxResults, traktConstants.maxPageSize)
+
+	// Paginate the search results until we've accumulated maxResults or there are no more results
+	pageNumber := 1
+
+	for len(results) < maxResults {
+		nextPageExists, err := os.searchPage(ctx, query, maxResults-len(results), pageNumber, pageSize, &results)
+		if err != nil {
+			return nil, err
+		}
+
+		if !nextPageExists {
+			break
+		}
+
+		pageNumber++
+	}
+
+	return results, nil
+}
+
+// searchPage performs a paginated search request to the Trakt API and processes the results.
+//
+// Parameters:
+//   - ctx: The context for the request, allowing for cancellation and timeouts.
+//   - query: The search query string.
+//   - maxResults: The maximum number of results to return. Must be greater than 0.
+//   - pageNumber: The page number to retrieve from the Trakt API.
+//   - pageSize: The number of results per page.
+//   - results: A pointer to a slice of SearchResult where the results will be appended.
+//
+// Returns:
+//   - bool: A boolean indicating whether there are more pages to retrieve.
+//   - error: An error if the search request failed or the response could not be processed.
+func (os *TraktSearcher) searchPage(ctx context.Context, query string, maxResults int, pageNumber int, pageSize int, results *[]SearchResult) (nextPage bool, err error) {
+	ctx, span := os.options.tracer.Start(ctx, "TraktSearcher.searchPage", trace.WithAttributes(
+		providerAttribute.String("trakt"),
+		pageAttribute.Int(pageNumber),
+	))
+
+	// The page is only recorded once the rate limiters admit its request, and timed from then on
+	var start time.Time
+	pageCount := 0
+	defer func() {
+		if !start.IsZero() {
+			os.options.metrics.ObservePage("trakt", time.Since(start), err)
+		}
+		endSpan(span, pageCount, err)
+	}()
+
+	opts := os.options.resolve(ctx)
+
+	// Narrow the search to a single type if the options ask for one
+	searchTypes := traktConstants.searchTypes
+	switch opts.Type {
+	case Movie:
+		searchTypes = traktConstants.movieType
+	case Series:
+		searchTypes = traktConstants.showType
+	}
+
+	// Build the URL for the search request
+	u, err := url.JoinPath(os.options.baseURLOr(traktConstants.baseURL), traktConstants.searchEndpoint, searchTypes)
+	if err != nil {
+		return false, err
+	}
+
+	endpoint, err := url.Parse(u)
+	if err != nil {
+		return false, err
+	}
+
+	params := url.Values{}
+	params.Add(traktConstants.searchParameter, query)
+	params.Add(traktConstants.pageParameter, fmt.Sprintf("%d", pageNumber))
+	params.Add(traktConstants.limitParameter, fmt.Sprintf("%d", pageSize))
+	if opts.Year != "" {
+		params.Add(traktConstants.yearsParameter, opts.Year)
+	}
+
+	endpoint.RawQuery = params.Encode()
+
+	// Create the request
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	// Add authentication headers
+	req.Header.Add(traktConstants.apiKeyHeader, os.clientID)
+	req.Header.Add(traktConstants.apiVersionHeader, traktConstants.apiVersion)
+
+	// Add the content type header, which Trakt requires
+	req.Header.Add("Content-Type", "application/json")
+
+	// Propagate the trace context to the provider
+	os.options.injectTraceContext(ctx, propagation.HeaderCarrier(req.Header))
+
+	// Wait for the rate limiters to admit the request
+	if err := os.options.wait(ctx); err != nil {
+		return false, err
+	}
+
+	start = time.Now()
+
+	// Perform the request
+	resp, err := os.client.Do(req)
+	if err != nil {
+		return false, NewSearchProviderErrorFromCause(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, NewSearchProviderErrorFromCause(err)
+	}
+
+	page, err := parseTraktSearchPage(resp.StatusCode, resp.Header.Get(traktConstants.pageCountHeader), body)
+	if err != nil {
+		return false, err
+	}
+
+	pageCount = len(page.Results)
+
+	log.Printf("Found %d results for query \"%s\" on page %d\n", len(page.Results), query, pageNumber)
+
+	// Filter the results
+	for _, searchResult := range page.Results {
+		// Trakt filters by year and type, but the options are checked again in case it didn't
+		if !opts.matches(searchResult) {
+			continue
+		}
+
+		maxResults--
+		if maxResults < 0 {
+			break
+		}
+
+		*results = append(*results, searchResult)
+	}
+
+	// An empty page means the count is stale, so stop rather than request pages up to it
+	if pageNumber < page.PageCount && maxResults > 0 && page.Listed > 0 {
+		return true, nil
+	}
+
+	// Otherwise, stop paginating
+	return false, nil
+}
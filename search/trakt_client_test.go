@@ -0,0 +1,370 @@
+package search_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+	"github.com/jdahan/gogettitles/search"
+)
+
+func TestNewTraktSearcher(t *testing.T) {
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+	if searcher == nil {
+		t.Fatal("expected non-nil TraktSearcher")
+	}
+}
+
+func TestTraktSearcher_Search_InvalidMaxResults(t *testing.T) {
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+	_, err := searcher.Search(context.Background(), "Matrix", 0)
+	var mrErr *search.InvalidMaxResultsError
+	if err == nil || !errors.As(err, &mrErr) {
+		t.Fatalf("expected invalid max results error, got %v", err)
+	}
+}
+
+func TestTraktSearcher_Search_ResultParsingError(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Matrix"
+	invalidJSON := `[{"type":`
+
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie,show").
+		MatchParam("query", query).
+		Reply(200).
+		BodyString(invalidJSON)
+
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+	_, err := searcher.Search(context.Background(), query, 5)
+	var rpErr *search.ResultParsingError
+	if err == nil || !errors.As(err, &rpErr) {
+		t.Fatalf("expected result parsing error, got %v", err)
+	}
+}
+
+func TestTraktSearcher_Search_ContextTimeout(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Matrix"
+
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie,show").
+		MatchParam("query", query).
+		Reply(200).
+		JSON(json.RawMessage(`[]`))
+
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Nanosecond)
+	defer cancel()
+
+	_, err := searcher.Search(ctx, query, 5)
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Fatalf("expected context deadline exceeded error, got %v", err)
+	}
+}
+
+func TestTraktSearcher_Search_Success(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Star Wars"
+	mockData, err := loadMockResponse("trakt_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie,show").
+		MatchParam("page", "1").
+		MatchParam("limit", "5").
+		MatchParam("query", query).
+		MatchHeader("Content-Type", "application/json").
+		MatchHeader("trakt-api-key", testAPIKey).
+		MatchHeader("trakt-api-version", "2").
+		Reply(200).
+		SetHeader("X-Pagination-Page-Count", "1").
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+	results, err := searcher.Search(context.Background(), query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+
+	expected := []search.SearchResult{
+		{Title: "Star Wars", Year: "1977", ImdbID: "tt0076759", ProviderId: "1092", Type: search.Movie, TmdbID: "11"},
+		{Title: "Star Wars: The Clone Wars", Year: "2008", ImdbID: "tt0458290", ProviderId: "1403", Type: search.Series, TmdbID: "4194"},
+	}
+	for i, result := range expected {
+		if results[i] != result {
+			t.Errorf("expected result %d to be %+v, got %+v", i, result, results[i])
+		}
+	}
+}
+
+func TestTraktSearcher_Search_Success_Max_Results_Greater_Than_Total(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Star Wars"
+	mockData, err := loadMockResponse("trakt_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie,show").
+		MatchParam("page", "1").
+		MatchParam("limit", "10").
+		MatchParam("query", query).
+		MatchHeader("trakt-api-key", testAPIKey).
+		Reply(200).
+		SetHeader("X-Pagination-Page-Count", "1").
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+	results, err := searcher.Search(context.Background(), query, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 5 {
+		t.Errorf("expected 5 results, got %d", len(results))
+	}
+}
+
+func TestTraktSearcher_Search_Success_Max_Results_Lower_Than_Total(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Star Wars"
+	mockData, err := loadMockResponse("trakt_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	// Trakt would honor the limit, but the searcher must not rely on it
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie,show").
+		MatchParam("page", "1").
+		MatchParam("limit", "1").
+		MatchParam("query", query).
+		MatchHeader("trakt-api-key", testAPIKey).
+		Reply(200).
+		SetHeader("X-Pagination-Page-Count", "5").
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+	results, err := searcher.Search(context.Background(), query, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected 1 result, got %d", len(results))
+	}
+}
+
+func TestTraktSearcher_Search_Success_Pagination(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Star Wars"
+
+	// Mock the first page of results (complete)
+	mockData, err := loadMockResponse("trakt_paginated_response_1.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie,show").
+		MatchParam("page", "1").
+		MatchParam("query", query).
+		MatchHeader("trakt-api-key", testAPIKey).
+		MatchHeader("trakt-api-version", "2").
+		Reply(200).
+		SetHeader("X-Pagination-Page-Count", "2").
+		JSON(json.RawMessage(mockData))
+
+	// Mock the second page of results (incomplete)
+	mockData, err = loadMockResponse("trakt_paginated_response_2.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie,show").
+		MatchParam("page", "2").
+		MatchParam("query", query).
+		MatchHeader("trakt-api-key", testAPIKey).
+		MatchHeader("trakt-api-version", "2").
+		Reply(200).
+		SetHeader("X-Pagination-Page-Count", "2").
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+	results, err := searcher.Search(context.Background(), query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 5 {
+		t.Errorf("expected 5 results, got %d", len(results))
+	}
+}
+
+func TestTraktSearcher_Search_NotAuthorized(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Star Wars"
+
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie,show").
+		MatchParam("query", query).
+		MatchHeader("trakt-api-key", testAPIKey).
+		Reply(403)
+
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+
+	_, err := searcher.Search(context.Background(), query, 5)
+	var spErr *search.SearchProviderError
+	if err == nil || !errors.As(err, &spErr) || !strings.Contains(err.Error(), "invalid client ID") {
+		t.Errorf("expected error containing 'invalid client ID', got %v", err)
+	}
+}
+
+func TestTraktSearcher_Search_RateLimitError(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Star Wars"
+
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie,show").
+		MatchParam("query", query).
+		Reply(429).
+		SetHeader("Retry-After", "1")
+
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+
+	_, err := searcher.Search(context.Background(), query, 5)
+	var rlErr *search.RateLimitError
+	if err == nil || !errors.As(err, &rlErr) {
+		t.Errorf("expected rate limit error, got %v", err)
+	}
+}
+
+func TestTraktSearcher_Search_SearchProviderError(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Matrix"
+
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie,show").
+		MatchParam("query", query).
+		ReplyError(&http.ProtocolError{ErrorString: "mock protocol error"})
+
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+	_, err := searcher.Search(context.Background(), query, 5)
+	if err == nil || !strings.Contains(err.Error(), "mock protocol error") {
+		t.Fatalf("expected search provider error, got %v", err)
+	}
+}
+
+func TestTraktSearcher_Search_YearAndTypeFilters(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Star Wars"
+	mockData, err := loadMockResponse("trakt_response.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading test data: %v", err)
+	}
+
+	// The filters are sent to Trakt, and applied again locally
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie").
+		MatchParam("query", query).
+		MatchParam("years", "1977").
+		Reply(200).
+		JSON(json.RawMessage(mockData))
+
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+	ctx := search.WithSearchOptions(context.Background(), search.SearchOptions{Year: "1977", Type: search.Movie})
+	results, err := searcher.Search(ctx, query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Star Wars" {
+		t.Errorf("expected only the 1977 movie, got %+v", results)
+	}
+}
+
+func TestTraktSearcher_Search_MissingIDsAndYear(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Matrix"
+	serverResponse := `[
+        {"type": "movie", "score": 100, "movie": {"title": "The Matrix Unreleased", "year": null, "ids": {"trakt": 1, "slug": "the-matrix-unreleased", "imdb": null, "tmdb": null}}},
+        {"type": "person", "score": 90, "person": {"name": "Keanu Reeves", "ids": {"trakt": 2}}},
+        {"type": "show", "score": 80, "show": {"title": "", "year": 2003, "ids": {"trakt": 3}}},
+        {"type": "movie", "score": 70},
+        {"type": "movie", "score": 60, "movie": {"title": "The Matrix", "year": 1999, "ids": {"trakt": 481, "slug": "the-matrix-1999", "imdb": "tt0133093", "tmdb": 603}}}
+    ]`
+
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie,show").
+		MatchParam("query", query).
+		Reply(200).
+		JSON(json.RawMessage(serverResponse))
+
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+	results, err := searcher.Search(context.Background(), query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Unknown IDs and years are left empty, and results of other types or without a title are skipped
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	if results[0].Year != "" || results[0].ImdbID != "" || results[0].TmdbID != "" || results[0].ProviderId != "1" {
+		t.Errorf("expected only the Trakt ID to be set, got %+v", results[0])
+	}
+
+	if results[1].ImdbID != "tt0133093" || results[1].TmdbID != "603" || results[1].ProviderId != "481" {
+		t.Errorf("expected all IDs to be set, got %+v", results[1])
+	}
+}
+
+func TestTraktSearcher_Search_EmptyPage(t *testing.T) {
+	defer gock.Off() // Flush pending mocks after test execution
+
+	query := "Matrix"
+
+	gock.New("https://api.trakt.tv").
+		Get("/search/movie,show").
+		MatchParam("query", query).
+		MatchParam("page", "1").
+		Reply(200).
+		SetHeader("X-Pagination-Page-Count", "500").
+		JSON(json.RawMessage(`[]`))
+
+	searcher := search.NewTraktSearcher(testAPIKey, http.DefaultClient)
+	results, err := searcher.Search(context.Background(), query, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %d", len(results))
+	}
+
+	if !gock.IsDone() {
+		t.Error("expected the first page to be requested")
+	}
+}
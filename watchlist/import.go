@@ -0,0 +1,115 @@
+package watchlist
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// A Match is an entry matched to a title.
+type Match struct {
+	// Entry is the imported entry.
+	Entry Entry
+	// Result is the title the entry was matched to.
+	Result search.SearchResult
+	// Confidence is how well the title matches the entry, from 0 to 1. Entries matched by IMDb ID have a confidence of 1.
+	Confidence float64
+}
+
+// A Miss is an entry that couldn't be matched to a title.
+type Miss struct {
+	// Entry is the imported entry.
+	Entry Entry
+	// Err is the reason the entry couldn't be matched, such as a search.NoMatchError or search.AmbiguousMatchError.
+	Err error
+}
+
+// A Report is the outcome of an import.
+type Report struct {
+	// Matched are the entries matched to a title, in order.
+	Matched []Match
+	// Unmatched are the entries that couldn't be matched, in order.
+	Unmatched []Miss
+}
+
+// An Importer matches imported entries to titles.
+type Importer struct {
+	resolver *search.Resolver
+	lookuper search.Lookuper
+}
+
+// NewImporter creates a new Importer.
+//
+// Parameters:
+//   - resolver: The Resolver used to match entries by title and year.
+//   - lookuper: The Lookuper used to match entries by IMDb ID, or nil to match every entry by title and year.
+//
+// Returns:
+//   - *Importer: A new instance of Importer.
+func NewImporter(resolver *search.Resolver, lookuper search.Lookuper) *Importer {
+	return &Importer{
+		resolver: resolver,
+		lookuper: lookuper,
+	}
+}
+
+// Import matches each entry to a title. Entries with an IMDb ID are looked up directly,
+// falling back to search if the ID is unknown; the others are resolved by title, year, and type.
+// Entries are matched concurrently, retrying those refused with a search.RateLimitError, as in search.SearchBatch.
+// A failing entry doesn't stop the import; it's reported as unmatched.
+//
+// Parameters:
+//   - ctx: The context for controlling cancellation and deadlines.
+//   - entries: The entries to match.
+//   - opts: The concurrency, limiter, and retries of the import. MaxResults and CheckpointPath are ignored.
+//
+// Returns:
+//   - *Report: The matched and unmatched entries.
+//   - error: ctx.Err() if the import was cancelled.
+func (im *Importer) Import(ctx context.Context, entries []Entry, opts search.BatchOptions) (*Report, error) {
+	matches := make([]*Match, len(entries))
+	errs, err := search.RunBatch(ctx, len(entries), opts, func(ctx context.Context, i int) error {
+		var err error
+		matches[i], err = im.match(ctx, entries[i])
+		return err
+	})
+
+	report := &Report{}
+	for i, entry := range entries {
+		if errs[i] != nil {
+			report.Unmatched = append(report.Unmatched, Miss{Entry: entry, Err: errs[i]})
+			continue
+		}
+
+		report.Matched = append(report.Matched, *matches[i])
+	}
+
+	return report, err
+}
+
+// match matches a single entry to a title.
+func (im *Importer) match(ctx context.Context, entry Entry) (*Match, error) {
+	if entry.ImdbID != "" && im.lookuper != nil {
+		details, err := im.lookuper.LookupByImdbID(ctx, entry.ImdbID)
+		if err == nil {
+			return &Match{Entry: entry, Result: details.SearchResult, Confidence: 1}, nil
+		}
+
+		var notFound *search.TitleNotFoundError
+		if !errors.As(err, &notFound) || entry.Title == "" {
+			return nil, err
+		}
+	}
+
+	if entry.Title == "" {
+		return nil, search.NewNoMatchError(entry.ImdbID)
+	}
+
+	resolution, err := im.resolver.Resolve(ctx, entry.Title, search.Hints{Year: entry.Year, Type: entry.Type})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Match{Entry: entry, Result: resolution.Result, Confidence: resolution.Confidence}, nil
+}
@@ -0,0 +1,171 @@
+// Package watchlist imports the lists users export from other services, such as Letterboxd, IMDb, and Trakt,
+// and matches each entry to a title.
+package watchlist
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jdahan/gogettitles/search"
+)
+
+// A Format is a list export format.
+type Format string
+
+const (
+	// Letterboxd is the format of Letterboxd's watched.csv and watchlist.csv exports.
+	Letterboxd Format = "letterboxd"
+	// IMDb is the format of IMDb list, watchlist, and ratings exports.
+	IMDb Format = "imdb"
+	// Trakt is the format of Trakt CSV exports.
+	Trakt Format = "trakt"
+)
+
+// An Entry is a single title of an imported list.
+type Entry struct {
+	// Line is the line number of the entry in the export, for reporting.
+	Line int
+	// Title is the title as listed.
+	Title string
+	// Year is the release year, or empty if the export doesn't include one.
+	Year string
+	// ImdbID is the IMDb ID (e.g. "tt0133093"), or empty if the export doesn't include one.
+	ImdbID string
+	// Type is the type of the title, or empty if the export doesn't include one.
+	Type search.ResultType
+}
+
+// columns maps the lowercase header names of each format to the Entry fields they hold.
+// A format is detected when all of its required columns are present.
+var columns = []struct {
+	format   Format
+	required []string
+	title    string
+	year     string
+	imdbID   []string
+	kind     string
+}{
+	{format: IMDb, required: []string{"const", "title", "title type"}, title: "title", year: "year", imdbID: []string{"const"}, kind: "title type"},
+	{format: Letterboxd, required: []string{"name", "year", "letterboxd uri"}, title: "name", year: "year"},
+	{format: Trakt, required: []string{"title", "year", "type"}, title: "title", year: "year", imdbID: []string{"imdb_id", "imdb"}, kind: "type"},
+}
+
+// titleTypes maps the lowercase title types of IMDb and Trakt exports to result types.
+var titleTypes = map[string]search.ResultType{
+	"movie":          search.Movie,
+	"tv movie":       search.Movie,
+	"short":          search.Movie,
+	"video":          search.Movie,
+	"tv series":      search.Series,
+	"tv mini series": search.Series,
+	"tv miniseries":  search.Series,
+	"show":           search.Series,
+	"tv episode":     search.Episode,
+	"episode":        search.Episode,
+}
+
+// UnknownFormatError is an error type that is returned when an export's columns don't match any known format.
+type UnknownFormatError struct {
+	header []string
+}
+
+// NewUnknownFormatError creates a new UnknownFormatError for the specified header.
+func NewUnknownFormatError(header []string) *UnknownFormatError {
+	return &UnknownFormatError{header: header}
+}
+
+// Error returns the error message associated with the UnknownFormatError.
+func (e *UnknownFormatError) Error() string {
+	return fmt.Sprintf("unknown export format with columns %q", e.header)
+}
+
+// Parse reads a CSV list export, detecting its format from its header.
+//
+// Parameters:
+//   - r: The reader to read the export from.
+//
+// Returns:
+//   - Format: The detected format.
+//   - []Entry: The entries of the list, in order.
+//   - error: An UnknownFormatError if the format isn't recognised, or an error if the CSV is malformed.
+func Parse(r io.Reader) (Format, []Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return "", nil, NewUnknownFormatError(nil)
+	} else if err != nil {
+		return "", nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	index := map[string]int{}
+	for i, name := range header {
+		// Exports from Windows tools may start with a byte order mark
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	for _, format := range columns {
+		if !hasColumns(index, format.required) {
+			continue
+		}
+
+		field := func(record []string, names ...string) string {
+			for _, name := range names {
+				if i, ok := index[name]; ok && i < len(record) {
+					if value := strings.TrimSpace(record[i]); value != "" {
+						return value
+					}
+				}
+			}
+
+			return ""
+		}
+
+		var entries []Entry
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return format.format, nil, fmt.Errorf("failed to read entry: %w", err)
+			}
+
+			line, _ := reader.FieldPos(0)
+			entry := Entry{
+				Line:   line,
+				Title:  field(record, format.title),
+				Year:   field(record, format.year),
+				ImdbID: field(record, format.imdbID...),
+				Type:   titleTypes[strings.ToLower(field(record, format.kind))],
+			}
+
+			if format.format == Letterboxd {
+				// Letterboxd only lists films
+				entry.Type = search.Movie
+			}
+
+			if entry.Title != "" || entry.ImdbID != "" {
+				entries = append(entries, entry)
+			}
+		}
+
+		return format.format, entries, nil
+	}
+
+	return "", nil, NewUnknownFormatError(header)
+}
+
+// hasColumns reports whether all the named columns are present.
+func hasColumns(index map[string]int, names []string) bool {
+	for _, name := range names {
+		if _, ok := index[name]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
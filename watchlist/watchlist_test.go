@@ -0,0 +1,251 @@
+package watchlist_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jdahan/gogettitles/search"
+	"github.com/jdahan/gogettitles/watchlist"
+)
+
+var catalogue = []search.SearchResult{
+	{Title: "The Matrix", Year: "1999", Type: search.Movie, ImdbID: "tt0133093"},
+	{Title: "Heat", Year: "1986", Type: search.Movie, ImdbID: "tt0091209"},
+	{Title: "Heat", Year: "1995", Type: search.Movie, ImdbID: "tt0113277"},
+	{Title: "Breaking Bad", Year: "2008", Type: search.Series, ImdbID: "tt0903747"},
+	{Title: "Crouching Tiger, Hidden Dragon", Year: "2000", Type: search.Movie, ImdbID: "tt0190332"},
+}
+
+// catalogueSearcher returns the titles in the catalogue containing the query that satisfy the Year and Type search options.
+type catalogueSearcher struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (cs *catalogueSearcher) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	cs.mu.Lock()
+	cs.queries = append(cs.queries, query)
+	cs.mu.Unlock()
+
+	opts := search.SearchOptionsFromContext(ctx)
+
+	var results []search.SearchResult
+	for _, result := range catalogue {
+		if strings.Contains(strings.ToLower(result.Title), strings.ToLower(query)) &&
+			(opts.Year == "" || result.Year == opts.Year) && (opts.Type == "" || result.Type == opts.Type) {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// catalogueLookuper looks up the titles in the catalogue by IMDb ID.
+type catalogueLookuper struct {
+	mu      sync.Mutex
+	lookups []string
+}
+
+func (cl *catalogueLookuper) LookupByImdbID(ctx context.Context, imdbID string) (*search.TitleDetails, error) {
+	cl.mu.Lock()
+	cl.lookups = append(cl.lookups, imdbID)
+	cl.mu.Unlock()
+
+	for _, result := range catalogue {
+		if result.ImdbID == imdbID {
+			return &search.TitleDetails{SearchResult: result}, nil
+		}
+	}
+
+	return nil, search.NewTitleNotFoundError(imdbID)
+}
+
+func (cl *catalogueLookuper) LookupByProviderID(ctx context.Context, providerID string, resultType search.ResultType) (*search.TitleDetails, error) {
+	return cl.LookupByImdbID(ctx, providerID)
+}
+
+func parseFixture(t *testing.T, name string) (watchlist.Format, []watchlist.Entry) {
+	t.Helper()
+
+	file, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer file.Close()
+
+	format, entries, err := watchlist.Parse(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return format, entries
+}
+
+func TestParse_Letterboxd(t *testing.T) {
+	format, entries := parseFixture(t, "letterboxd_watched.csv")
+
+	if format != watchlist.Letterboxd {
+		t.Errorf("expected Letterboxd format, got %q", format)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+
+	want := watchlist.Entry{Line: 4, Title: "Crouching Tiger, Hidden Dragon", Year: "2000", Type: search.Movie}
+	if entries[2] != want {
+		t.Errorf("expected %+v, got %+v", want, entries[2])
+	}
+}
+
+func TestParse_IMDb(t *testing.T) {
+	format, entries := parseFixture(t, "imdb_list.csv")
+
+	if format != watchlist.IMDb {
+		t.Errorf("expected IMDb format, got %q", format)
+	}
+
+	want := watchlist.Entry{Line: 3, Title: "Breaking Bad", Year: "2008", ImdbID: "tt0903747", Type: search.Series}
+	if len(entries) != 3 || entries[1] != want {
+		t.Errorf("expected %+v, got %+v", want, entries)
+	}
+}
+
+func TestParse_Trakt(t *testing.T) {
+	format, entries := parseFixture(t, "trakt_watchlist.csv")
+
+	if format != watchlist.Trakt {
+		t.Errorf("expected Trakt format, got %q", format)
+	}
+
+	want := watchlist.Entry{Line: 2, Title: "Heat", Year: "1995", ImdbID: "tt0113277", Type: search.Movie}
+	if len(entries) != 3 || entries[0] != want {
+		t.Errorf("expected %+v, got %+v", want, entries)
+	}
+}
+
+func TestParse_UnknownFormat(t *testing.T) {
+	_, _, err := watchlist.Parse(strings.NewReader("foo,bar\n1,2\n"))
+
+	var unknown *watchlist.UnknownFormatError
+	if !errors.As(err, &unknown) {
+		t.Errorf("expected UnknownFormatError, got %v", err)
+	}
+}
+
+func TestImporter_Import_Letterboxd(t *testing.T) {
+	_, entries := parseFixture(t, "letterboxd_watched.csv")
+	importer := watchlist.NewImporter(search.NewResolver(&catalogueSearcher{}, search.ResolverConfig{}), nil)
+
+	report, err := importer.Import(context.Background(), entries, search.BatchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var matched []string
+	for _, match := range report.Matched {
+		matched = append(matched, match.Result.ImdbID)
+	}
+	if strings.Join(matched, ",") != "tt0133093,tt0113277,tt0190332" {
+		t.Errorf("unexpected matches: %v", matched)
+	}
+
+	var noMatch *search.NoMatchError
+	if len(report.Unmatched) != 1 || report.Unmatched[0].Entry.Title != "Unknown Student Film" || !errors.As(report.Unmatched[0].Err, &noMatch) {
+		t.Errorf("expected the student film to be unmatched, got %+v", report.Unmatched)
+	}
+}
+
+func TestImporter_Import_IMDbMatchesByID(t *testing.T) {
+	_, entries := parseFixture(t, "imdb_list.csv")
+	searcher, lookuper := &catalogueSearcher{}, &catalogueLookuper{}
+	importer := watchlist.NewImporter(search.NewResolver(searcher, search.ResolverConfig{}), lookuper)
+
+	report, err := importer.Import(context.Background(), entries, search.BatchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Matched) != 3 || len(report.Unmatched) != 0 {
+		t.Fatalf("expected all entries to match, got %+v", report)
+	}
+	if report.Matched[0].Confidence != 1 || report.Matched[1].Result.ImdbID != "tt0903747" {
+		t.Errorf("expected exact matches by ID, got %+v", report.Matched)
+	}
+
+	// The unknown ID falls back to search by title and year
+	if report.Matched[2].Result.ImdbID != "tt0113277" || len(searcher.queries) != 1 || searcher.queries[0] != "Heat" {
+		t.Errorf("expected the unknown ID to be searched, got %+v after %v", report.Matched[2], searcher.queries)
+	}
+}
+
+func TestImporter_Import_TraktAmbiguous(t *testing.T) {
+	_, entries := parseFixture(t, "trakt_watchlist.csv")
+	importer := watchlist.NewImporter(search.NewResolver(&catalogueSearcher{}, search.ResolverConfig{}), &catalogueLookuper{})
+
+	report, err := importer.Import(context.Background(), entries, search.BatchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Matched) != 2 {
+		t.Errorf("expected 2 matches, got %+v", report.Matched)
+	}
+
+	var ambiguous *search.AmbiguousMatchError
+	if len(report.Unmatched) != 1 || !errors.As(report.Unmatched[0].Err, &ambiguous) {
+		t.Errorf("expected Heat without a year to be ambiguous, got %+v", report.Unmatched)
+	}
+}
+
+func TestImporter_Import_Cancelled(t *testing.T) {
+	_, entries := parseFixture(t, "letterboxd_watched.csv")
+	importer := watchlist.NewImporter(search.NewResolver(&catalogueSearcher{}, search.ResolverConfig{}), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := importer.Import(ctx, entries, search.BatchOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// flakySearcher refuses the first calls with a RateLimitError, then delegates to a catalogueSearcher.
+type flakySearcher struct {
+	catalogueSearcher
+	refusals atomic.Int32
+}
+
+func (fs *flakySearcher) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	if fs.refusals.Add(-1) >= 0 {
+		return nil, search.NewRateLimitError("slow down")
+	}
+
+	return fs.catalogueSearcher.Search(ctx, query, maxResults)
+}
+
+func TestImporter_Import_RetriesRateLimits(t *testing.T) {
+	_, entries := parseFixture(t, "letterboxd_watched.csv")
+	searcher := &flakySearcher{}
+	searcher.refusals.Store(2)
+	importer := watchlist.NewImporter(search.NewResolver(searcher, search.ResolverConfig{}), nil)
+
+	report, err := importer.Import(context.Background(), entries, search.BatchOptions{Concurrency: 2, RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The rate limited entries are retried, and the report keeps the order of the entries
+	var matched []string
+	for _, match := range report.Matched {
+		matched = append(matched, match.Result.ImdbID)
+	}
+	if strings.Join(matched, ",") != "tt0133093,tt0113277,tt0190332" || len(report.Unmatched) != 1 {
+		t.Errorf("unexpected report after rate limits: %v, %+v", matched, report.Unmatched)
+	}
+}